@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+	"github.com/ahmaruff/tada/internal/parser"
+	"github.com/ahmaruff/tada/internal/processor"
+	"github.com/ahmaruff/tada/internal/writer"
+	"github.com/spf13/cobra"
+)
+
+var addCmd = &cobra.Command{
+	Use:   "add <title>",
+	Short: "Add a task to Backlog",
+	Long: `Add a new task to Backlog with a generated unique ID.
+
+Use --todo to also schedule the task in Todo under a date header, e.g.
+--todo today or --todo 2025-01-20.`,
+	Example: `  tada add "Fix login bug" -p backend
+  tada add "Fix login bug" -p backend --todo today`,
+	Args: cobra.ExactArgs(1),
+	Run:  runAdd,
+}
+
+var (
+	addProject     string
+	addID          string
+	addTodo        string
+	addDescription []string
+)
+
+func init() {
+	addCmd.Flags().StringVarP(&addProject, "project", "p", "", "Project name")
+	addCmd.Flags().StringVar(&addID, "id", "", "Task ID (generated when empty)")
+	addCmd.Flags().StringVar(&addTodo, "todo", "", "Also add to Todo under this date (today or YYYY-MM-DD)")
+	addCmd.Flags().StringArrayVarP(&addDescription, "desc", "d", nil, "Description line (can be repeated)")
+}
+
+func runAdd(cmd *cobra.Command, args []string) {
+	inputFile := rootInputFile
+	startJournal(inputFile)
+
+	title := strings.TrimSpace(args[0])
+	if title == "" || strings.ContainsAny(title, "\r\n") {
+		log.Fatalf("Task title must be a single non-empty line")
+	}
+
+	var todoDate *time.Time
+	if addTodo != "" {
+		date, err := parseDateArg(addTodo)
+		if err != nil {
+			log.Fatalf("Invalid --todo date: %v", err)
+		}
+		todoDate = &date
+	}
+
+	sections, err := parser.ParseFile(inputFile)
+	if err != nil {
+		log.Fatalf("Failed to parse input file: %v", err)
+	}
+
+	id := strings.TrimPrefix(addID, "#")
+	if id == "" {
+		id = processor.NextTaskID(sections)
+	} else if processor.TaskIDs(sections)[id] {
+		log.Fatalf("Task ID #%s is already in use", id)
+	}
+
+	task := model.Task{
+		ID:          id,
+		Title:       title,
+		Project:     addProject,
+		Status:      model.StatusTodo,
+		Description: addDescription,
+		SubTasks:    []model.Subtask{},
+	}
+
+	sections = processor.AddTask(sections, task, todoDate)
+
+	err = writer.WriteInputFile(sections, inputFile)
+	if err != nil {
+		log.Fatalf("Failed to write updated input file: %v", err)
+	}
+
+	if todoDate != nil {
+		fmt.Printf("Added task #%s to Backlog and Todo (%s) in %s\n", id, todoDate.Format("2006-01-02"), inputFile)
+	} else {
+		fmt.Printf("Added task #%s to Backlog in %s\n", id, inputFile)
+	}
+}
+
+// parseDateArg parses a date given on the command line: "today",
+// "yesterday", "tomorrow" or YYYY-MM-DD.
+func parseDateArg(value string) (time.Time, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch strings.ToLower(value) {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	return time.Parse("2006-01-02", value)
+}
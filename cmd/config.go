@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect configuration",
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective settings",
+	Long: `Print the effective settings after reading config files, environment
+variables and flags, along with the config files that were loaded.`,
+	Args: cobra.NoArgs,
+	Run:  runConfigShow,
+}
+
+func init() {
+	configCmd.AddCommand(configShowCmd)
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) {
+	if len(cfg.Files) == 0 {
+		fmt.Println("# No config file found, using defaults")
+	}
+	for _, file := range cfg.Files {
+		fmt.Printf("# Loaded from %s\n", file)
+	}
+
+	cfg.Input = rootInputFile
+
+	for _, value := range cfg.Values() {
+		fmt.Printf("%s: %s\n", value[0], value[1])
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/exchange"
+	"github.com/ahmaruff/tada/internal/model"
+	"github.com/ahmaruff/tada/internal/parser"
+	"github.com/ahmaruff/tada/internal/processor"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Export tasks for other tools",
+	Long: `Export every section of the input file to stdout.
+
+Formats:
+  json  versioned JSON document with sections, date headers, tasks,
+        descriptions and subtasks; read back with tada import
+  csv   timesheet with one row per task per day it was active: every day
+        of a Backlog/Archives task's date range and the date of every
+        Todo/Done entry. Columns: date, project, id, title, status, hours
+  ics   iCalendar file with a VTODO per dated task (and an all-day VEVENT
+        with --events), after merging Todo/Done dates into Backlog. UIDs
+        come from task IDs, so calendar apps update entries on re-import
+
+Free text between tasks is not exported.`,
+	Example: `  tada export --format json > tasks.json
+  tada export --format csv --from 2025-09-01 --to 2025-09-30 -p crm --hours 8
+  tada export --format ics --events > ~/calendars/tada.ics`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runExport,
+}
+
+var (
+	exportFormat   string
+	exportFrom     string
+	exportTo       string
+	exportProjects []string
+	exportHours    float64
+	exportEvents   bool
+)
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "json", "Output format: json, csv or ics")
+	exportCmd.Flags().StringVar(&exportFrom, "from", "", "csv, ics: only from this date (YYYY-MM-DD, today, yesterday)")
+	exportCmd.Flags().StringVar(&exportTo, "to", "", "csv, ics: only up to this date")
+	exportCmd.Flags().StringSliceVarP(&exportProjects, "project", "p", nil, "csv, ics: only these projects (repeatable)")
+	exportCmd.Flags().Float64Var(&exportHours, "hours", 0, "csv: hours per day, split evenly across the tasks active that day")
+	exportCmd.Flags().BoolVar(&exportEvents, "events", false, "ics: also write an all-day event per task")
+}
+
+func runExport(cmd *cobra.Command, args []string) {
+	inputFile := inputPath(args)
+
+	sections, err := parser.ParseFile(inputFile)
+	if err != nil {
+		log.Fatalf("Failed to parse input file: %v", err)
+	}
+
+	switch exportFormat {
+	case "json":
+		err = exchange.ExportJSON(os.Stdout, sections)
+	case "csv":
+		err = exchange.ExportCSV(os.Stdout, exchange.TimesheetRows(sections, timesheetOptions()))
+	case "ics":
+		options := timesheetOptions()
+		tasks := processor.FilterTasks(processor.ConsolidateTasks(sections), processor.TaskFilter{
+			Sections: []model.SectionName{model.SectionBacklog, model.SectionArchives},
+			Projects: options.Projects,
+			From:     options.From,
+			To:       options.To,
+		})
+		err = exchange.ExportICS(os.Stdout, tasks, exchange.ICSOptions{Events: exportEvents, Now: time.Now()})
+	default:
+		log.Fatalf("Unknown format %q, expected json, csv or ics", exportFormat)
+	}
+	if err != nil {
+		log.Fatalf("Failed to export: %v", err)
+	}
+}
+
+func timesheetOptions() exchange.TimesheetOptions {
+	options := exchange.TimesheetOptions{Projects: exportProjects, HoursPerDay: exportHours}
+
+	if exportFrom != "" {
+		from, err := parseDateArg(exportFrom)
+		if err != nil {
+			log.Fatalf("Invalid --from: %v", err)
+		}
+		options.From = &from
+	}
+
+	if exportTo != "" {
+		to, err := parseDateArg(exportTo)
+		if err != nil {
+			log.Fatalf("Invalid --to: %v", err)
+		}
+		options.To = &to
+	}
+
+	if exportHours < 0 {
+		log.Fatalf("Invalid --hours: must not be negative")
+	}
+
+	return options
+}
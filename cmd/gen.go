@@ -2,10 +2,15 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/ahmaruff/tada/internal/archive"
 	"github.com/ahmaruff/tada/internal/model"
 	"github.com/ahmaruff/tada/internal/parser"
 	"github.com/ahmaruff/tada/internal/processor"
@@ -24,39 +29,139 @@ This command runs the complete workflow:
 3. Move completed Backlog tasks to Archives
 4. Generate report from Archives
 5. Clear Archives
-6. Update input file`,
+6. Update input file
+
+Use --keep-archives to leave the archived tasks in the input file, and
+--archive-dir to also append them to a permanent archive store: one
+markdown file per year (archive/2025.md) or, with --archive-split month,
+per month (archive/2025-09.md). Tasks already in the store are skipped.
+
+Use --from/--to, --week, --last-week, --month or --sprint N to report a
+period instead: every task whose dates overlap it is taken from Backlog,
+Archives, Done and the archive store, and the input file is left untouched.
+
+Use --read-only to consolidate and archive in memory and write only the
+report, or --stdout to print the report instead, e.g. to paste it into chat.
+Neither changes the input file or the archive store.
+
+Use --format html for a self-contained HTML page to mail to stakeholders,
+grouped by project unless --group-by says otherwise. --template then takes
+an html/template file.
+
+Use --template to render the report through your own Go text/template file
+instead of the built-in "# PROJECT - Title" layout. See the README for the
+data and helper functions available to templates.
+
+Use --group-by project, date or status to put the tasks under one heading
+per group, with the number of tasks in each. --project-order (or
+project_order in the config file) lists projects to put first.`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runGen,
 }
 
 var (
-	genInputFile string
 	genOutputDir string
+	genTemplate  string
+	genFormat    string
+	genGroupBy   string
+	genProjects  []string
+	genKeep      bool
+	genStoreDir  string
+	genSplit     string
+	genFrom      string
+	genTo        string
+	genWeek      bool
+	genLastWeek  bool
+	genMonth     bool
+	genSprint    int
+	genStdout    bool
+	genReadOnly  bool
 	genDryRun    bool
 	genVerbose   bool
 )
 
+// genLog receives progress messages, kept apart from a report on stdout.
+var genLog io.Writer = os.Stdout
+
 func init() {
-	genCmd.Flags().StringVarP(&genInputFile, "input", "i", "input.md", "Input markdown file")
 	genCmd.Flags().StringVarP(&genOutputDir, "output", "o", ".", "Output directory for report")
+	genCmd.Flags().StringVarP(&genTemplate, "template", "t", "", "Go text/template file for the report (default: built-in layout)")
+	genCmd.Flags().StringVarP(&genFormat, "format", "f", "markdown", "Report format: markdown or html")
+	genCmd.Flags().StringVarP(&genGroupBy, "group-by", "g", "", "Group the report by project, date or status")
+	genCmd.Flags().StringSliceVar(&genProjects, "project-order", nil, "Projects listed first with --group-by project, e.g. tada,crm")
+	genCmd.Flags().BoolVar(&genKeep, "keep-archives", false, "Leave archived tasks in the input file after the report")
+	genCmd.Flags().StringVar(&genStoreDir, "archive-dir", "", "Also append archived tasks to markdown files in this directory")
+	genCmd.Flags().StringVar(&genSplit, "archive-split", "year", "One archive file per year or month")
+	genCmd.Flags().StringVar(&genFrom, "from", "", "Report tasks from this date (YYYY-MM-DD, today, yesterday)")
+	genCmd.Flags().StringVar(&genTo, "to", "", "Report tasks up to this date (default: today)")
+	genCmd.Flags().BoolVar(&genWeek, "week", false, "Report this week, Monday to Sunday")
+	genCmd.Flags().BoolVar(&genLastWeek, "last-week", false, "Report last week, Monday to Sunday")
+	genCmd.Flags().BoolVar(&genMonth, "month", false, "Report this calendar month")
+	genCmd.Flags().IntVar(&genSprint, "sprint", 0, "Report sprint N, counted from sprint_start in the config file")
+	genCmd.MarkFlagsMutuallyExclusive("from", "week", "last-week", "month", "sprint")
+	genCmd.MarkFlagsMutuallyExclusive("to", "week", "last-week", "month", "sprint")
+	genCmd.Flags().BoolVar(&genStdout, "stdout", false, "Print the report instead of writing a file; implies --read-only")
+	genCmd.Flags().BoolVar(&genReadOnly, "read-only", false, "Write only the report, leaving the input file and archive store untouched")
 	genCmd.Flags().BoolVar(&genDryRun, "dry-run", false, "Preview what would be processed without making changes")
 	genCmd.Flags().BoolVarP(&genVerbose, "verbose", "v", false, "Verbose output")
 }
 
 func runGen(cmd *cobra.Command, args []string) {
 	// Use positional argument if provided
-	inputFile := genInputFile
-	if len(args) > 0 {
-		inputFile = args[0]
+	inputFile := inputPath(args)
+	startJournal(inputFile)
+
+	if genStdout {
+		genReadOnly = true
+		genLog = os.Stderr
+	}
+
+	groupBy, err := writer.ParseGroupBy(genGroupBy)
+	if err != nil {
+		log.Fatalf("Invalid --group-by: %v", err)
+	}
+
+	var tmpl writer.Renderer
+	switch genFormat {
+	case "markdown", "md":
+		tmpl, err = writer.LoadTemplate(genTemplate)
+	case "html":
+		tmpl, err = writer.LoadHTMLTemplate(genTemplate)
+		// HTML reports are meant for stakeholders, who read by project
+		if groupBy == writer.GroupByNone {
+			groupBy = writer.GroupByProject
+		}
+	default:
+		log.Fatalf("Unknown format %q, expected markdown or html", genFormat)
+	}
+	if err != nil {
+		log.Fatalf("Invalid --template: %v", err)
+	}
+
+	split, err := archive.ParseSplit(genSplit)
+	if err != nil {
+		log.Fatalf("Invalid --archive-split: %v", err)
+	}
+	store := archive.Store{Dir: genStoreDir, Split: split}
+
+	from, to, err := reportPeriod(time.Now())
+	if err != nil {
+		log.Fatalf("Invalid report period: %v", err)
+	}
+
+	reportOptions := writer.ReportOptions{
+		Template:     tmpl,
+		GroupBy:      groupBy,
+		ProjectOrder: genProjects,
 	}
 
 	if genVerbose {
-		fmt.Printf("Starting tada gen with input: %s\n", inputFile)
+		fmt.Fprintf(genLog, "Starting tada gen with input: %s\n", inputFile)
 	}
 
 	// 1. Parse input file
 	if genVerbose {
-		fmt.Println("1. Parsing input file...")
+		fmt.Fprintln(genLog, "1. Parsing input file...")
 	}
 
 	sections, err := parser.ParseFile(inputFile)
@@ -65,25 +170,32 @@ func runGen(cmd *cobra.Command, args []string) {
 	}
 
 	if genVerbose {
-		fmt.Printf("   Parsed %d sections\n", len(sections))
+		fmt.Fprintf(genLog, "   Parsed %d sections\n", len(sections))
 		for _, section := range sections {
-			fmt.Printf("   - %s: %d tasks\n", section.Name, len(section.Tasks))
+			fmt.Fprintf(genLog, "   - %s: %d tasks\n", section.Name, len(section.Tasks))
 		}
 	}
 
 	// 2. Consolidate tasks
 	if genVerbose {
-		fmt.Println("\n2. Consolidating tasks...")
+		fmt.Fprintln(genLog, "\n2. Consolidating tasks...")
 	}
 
 	sections = processor.ConsolidateTasks(sections)
 	if genVerbose {
-		fmt.Println("   Tasks consolidated")
+		fmt.Fprintln(genLog, "   Tasks consolidated")
+	}
+
+	// A report for a period is built in memory and leaves the input file
+	// untouched
+	if from != nil {
+		runPeriodReport(sections, store, reportOptions, *from, *to)
+		return
 	}
 
 	// 3. Move completed Backlog tasks to Archives
 	if genVerbose {
-		fmt.Println("\n3. Moving completed tasks to Archives...")
+		fmt.Fprintln(genLog, "\n3. Moving completed tasks to Archives...")
 	}
 
 	sections = processor.MoveCompletedBacklogToArchives(sections)
@@ -98,89 +210,78 @@ func runGen(cmd *cobra.Command, args []string) {
 	}
 
 	if genVerbose {
-		fmt.Printf("   Moved to Archives: %d tasks\n", archivedCount)
+		fmt.Fprintf(genLog, "   Moved to Archives: %d tasks\n", archivedCount)
 	}
 
 	if archivedCount == 0 {
-		fmt.Println("No completed tasks found to archive. Report not generated.")
-		return
-	}
-
-	if genDryRun {
-		fmt.Printf("DRY RUN: Would generate report from %d archived tasks\n", archivedCount)
-		if genVerbose {
-			fmt.Println("Archived tasks:")
-			for _, section := range sections {
-				if section.Name == model.SectionArchives {
-					for i, task := range section.Tasks {
-						dateStr := "no date"
-						if task.StartDate != nil {
-							dateStr = task.StartDate.Format("2006-01-02")
-						}
-						fmt.Printf("   %d. %s [%v] (%s)\n", i+1, task.Title, task.Status, dateStr)
-					}
-					break
-				}
-			}
-		}
+		fmt.Fprintln(genLog, "No completed tasks found to archive. Report not generated.")
 		return
 	}
 
 	// 4. Generate report with dynamic filename
-	if genVerbose {
-		fmt.Println("\n4. Generating report...")
+	if genVerbose && !genDryRun {
+		fmt.Fprintln(genLog, "\n4. Generating report...")
 	}
 
 	// Find date range from Archives
-	var earliestDate, latestDate *time.Time
-	for _, section := range sections {
-		if section.Name == "Archives" {
-			for _, task := range section.Tasks {
-				if task.StartDate != nil {
-					if earliestDate == nil || task.StartDate.Before(*earliestDate) {
-						earliestDate = task.StartDate
-					}
-					if task.EndDate != nil {
-						if latestDate == nil || task.EndDate.After(*latestDate) {
-							latestDate = task.EndDate
-						}
-					} else {
-						if latestDate == nil || task.StartDate.After(*latestDate) {
-							latestDate = task.StartDate
-						}
-					}
-				}
-			}
-		}
+	report, err := writer.NewReportData(sections, reportOptions)
+	if err != nil {
+		log.Fatalf("Failed to group report: %v", err)
 	}
+	earliestDate, latestDate := report.Start, report.End
 
 	// Generate filename
-	var outputFile string
-	if earliestDate != nil && latestDate != nil {
-		filename := fmt.Sprintf("report_%s_%s.md",
-			earliestDate.Format("2006-01-02"),
-			latestDate.Format("2006-01-02"))
-		outputFile = filepath.Join(genOutputDir, filename)
-	} else {
-		outputFile = filepath.Join(genOutputDir, "report.md")
+	outputFile := reportPath(earliestDate, latestDate)
+
+	if genDryRun {
+		previewGen(sections, inputFile, outputFile, reportOptions, archivedCount, store)
+		return
 	}
 
-	err = writer.WriteOutputFile(sections, outputFile)
-	if err != nil {
-		log.Fatalf("Failed to write output file: %v", err)
+	writeReport(sections, outputFile, reportOptions)
+
+	if genReadOnly {
+		if genVerbose {
+			fmt.Fprintln(genLog, "\nRead-only: input file and archive store left untouched")
+		}
+		return
 	}
 
-	fmt.Printf("Report generated: %s\n", outputFile)
+	// 5. Store archived tasks, then clear Archives section
+	if store.Dir != "" {
+		if genVerbose {
+			fmt.Fprintln(genLog, "\n5. Storing archived tasks...")
+		}
 
-	// 5. Clear Archives section
-	if genVerbose {
-		fmt.Println("\n5. Clearing Archives...")
+		appended, err := store.Append(report.Tasks, time.Now())
+		if err != nil {
+			log.Fatalf("Failed to store archived tasks: %v", err)
+		}
+
+		paths := make([]string, 0, len(appended))
+		for path := range appended {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			fmt.Fprintf(genLog, "Archived %d tasks to %s\n", appended[path], path)
+		}
+	}
+
+	if genKeep {
+		if genVerbose {
+			fmt.Fprintln(genLog, "   Keeping Archives in the input file")
+		}
+	} else {
+		if genVerbose {
+			fmt.Fprintln(genLog, "\n5. Clearing Archives...")
+		}
+		sections = processor.ClearArchives(sections)
 	}
-	sections = processor.ClearArchives(sections)
 
 	// 6. Update input file
 	if genVerbose {
-		fmt.Println("\n6. Updating input file...")
+		fmt.Fprintln(genLog, "\n6. Updating input file...")
 	}
 	err = writer.WriteInputFile(sections, inputFile)
 	if err != nil {
@@ -188,9 +289,186 @@ func runGen(cmd *cobra.Command, args []string) {
 	}
 
 	if genVerbose {
-		fmt.Printf("   Updated input file: %s\n", inputFile)
-		fmt.Println("\nProcessing complete!")
+		fmt.Fprintf(genLog, "   Updated input file: %s\n", inputFile)
+		fmt.Fprintln(genLog, "\nProcessing complete!")
 	} else {
-		fmt.Printf("Input file updated: %s\n", inputFile)
+		fmt.Fprintf(genLog, "Input file updated: %s\n", inputFile)
+	}
+}
+
+// reportPeriod returns the period selected by the period flags, or nil
+// dates if none was given.
+func reportPeriod(now time.Time) (*time.Time, *time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var from, to time.Time
+	switch {
+	case genWeek, genLastWeek:
+		// Weeks start on Monday
+		from = today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+		if genLastWeek {
+			from = from.AddDate(0, 0, -7)
+		}
+		to = from.AddDate(0, 0, 6)
+	case genMonth:
+		from = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		to = from.AddDate(0, 1, -1)
+	case genSprint != 0:
+		if genSprint < 1 {
+			return nil, nil, fmt.Errorf("--sprint must be 1 or more, got %d", genSprint)
+		}
+		if cfg.SprintStart == "" {
+			return nil, nil, fmt.Errorf("--sprint needs sprint_start in the config file")
+		}
+		start, err := time.Parse("2006-01-02", cfg.SprintStart)
+		if err != nil {
+			return nil, nil, fmt.Errorf("sprint_start: %w", err)
+		}
+		from = start.AddDate(0, 0, (genSprint-1)*cfg.SprintDays)
+		to = from.AddDate(0, 0, cfg.SprintDays-1)
+	case genFrom != "":
+		var err error
+		if from, err = parseDateArg(genFrom); err != nil {
+			return nil, nil, fmt.Errorf("--from: %w", err)
+		}
+		to = today
+		if genTo != "" {
+			if to, err = parseDateArg(genTo); err != nil {
+				return nil, nil, fmt.Errorf("--to: %w", err)
+			}
+		}
+		if to.Before(from) {
+			return nil, nil, fmt.Errorf("--to %s is before --from %s", to.Format("2006-01-02"), from.Format("2006-01-02"))
+		}
+	case genTo != "":
+		return nil, nil, fmt.Errorf("--to needs --from")
+	default:
+		return nil, nil, nil
+	}
+
+	return &from, &to, nil
+}
+
+// runPeriodReport writes a report of every task overlapping from..to
+// without changing the input file.
+func runPeriodReport(sections []model.Section, store archive.Store, options writer.ReportOptions, from, to time.Time) {
+	period := from.Format("2006-01-02") + " - " + to.Format("2006-01-02")
+
+	var stored []model.Task
+	if store.Dir != "" {
+		var err error
+		if stored, err = store.Load(); err != nil {
+			log.Fatalf("Failed to read archive store: %v", err)
+		}
+	}
+
+	tasks := processor.CollectPeriodTasks(sections, stored, from, to)
+	if genVerbose {
+		fmt.Fprintf(genLog, "\n3. Selected %d tasks in %s\n", len(tasks), period)
+	}
+
+	if len(tasks) == 0 {
+		fmt.Fprintf(genLog, "No tasks found in %s. Report not generated.\n", period)
+		return
+	}
+
+	outputFile := reportPath(&from, &to)
+	options.Start, options.End = &from, &to
+	reportSections := []model.Section{{Name: model.SectionArchives, Tasks: tasks}}
+
+	if genDryRun {
+		fmt.Fprintf(genLog, "DRY RUN: Would generate %s from %d tasks in %s\n", outputFile, len(tasks), period)
+		if !genStdout {
+			content, err := writer.GenerateOutputMarkdown(reportSections, options)
+			if err != nil {
+				log.Fatalf("Failed to generate report: %v", err)
+			}
+			printDiff(outputFile, content)
+		}
+		return
+	}
+
+	writeReport(reportSections, outputFile, options)
+}
+
+// previewGen prints what gen would do without --dry-run: the archived tasks
+// and the diffs of the report and the input file.
+func previewGen(sections []model.Section, inputFile, outputFile string, options writer.ReportOptions, archivedCount int, store archive.Store) {
+	fmt.Fprintf(genLog, "DRY RUN: Would generate report from %d archived tasks\n", archivedCount)
+	if genReadOnly {
+		fmt.Fprintln(genLog, "DRY RUN: Would leave the input file untouched")
+	} else if store.Dir != "" {
+		fmt.Fprintf(genLog, "DRY RUN: Would append archived tasks to %s\n", store.Dir)
+	}
+	if genKeep && !genReadOnly {
+		fmt.Fprintln(genLog, "DRY RUN: Would keep Archives in the input file")
+	}
+	if genVerbose {
+		fmt.Fprintln(genLog, "Archived tasks:")
+		for _, section := range sections {
+			if section.Name == model.SectionArchives {
+				for i, task := range section.Tasks {
+					dateStr := "no date"
+					if task.StartDate != nil {
+						dateStr = task.StartDate.Format("2006-01-02")
+					}
+					fmt.Fprintf(genLog, "   %d. %s [%v] (%s)\n", i+1, task.Title, task.Status, dateStr)
+				}
+				break
+			}
+		}
+	}
+
+	if !genStdout {
+		content, err := writer.GenerateOutputMarkdown(sections, options)
+		if err != nil {
+			log.Fatalf("Failed to generate report: %v", err)
+		}
+		printDiff(outputFile, content)
+	}
+
+	if !genReadOnly {
+		if !genKeep {
+			sections = processor.ClearArchives(sections)
+		}
+		printDiff(inputFile, writer.GenerateInputMarkdown(sections))
+	}
+}
+
+// writeReport writes the report of the Archives section to outputFile, or
+// to stdout with --stdout.
+func writeReport(sections []model.Section, outputFile string, options writer.ReportOptions) {
+	if genStdout {
+		content, err := writer.GenerateOutputMarkdown(sections, options)
+		if err != nil {
+			log.Fatalf("Failed to generate report: %v", err)
+		}
+		fmt.Print(content)
+		return
+	}
+
+	if err := writer.WriteOutputFile(sections, outputFile, options); err != nil {
+		log.Fatalf("Failed to write output file: %v", err)
+	}
+	fmt.Fprintf(genLog, "Report generated: %s\n", outputFile)
+}
+
+// reportPath names the report after its date range using the configured
+// filename pattern, or report.md (report.html) without dates.
+func reportPath(start, end *time.Time) string {
+	if start == nil || end == nil {
+		if genFormat == "html" {
+			return filepath.Join(genOutputDir, "report.html")
+		}
+		return filepath.Join(genOutputDir, "report.md")
+	}
+
+	filename := strings.NewReplacer(
+		"{start}", start.Format("2006-01-02"),
+		"{end}", end.Format("2006-01-02"),
+	).Replace(cfg.Filename)
+	if genFormat == "html" {
+		filename = strings.TrimSuffix(filename, ".md") + ".html"
 	}
+	return filepath.Join(genOutputDir, filename)
 }
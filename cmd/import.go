@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ahmaruff/tada/internal/exchange"
+	"github.com/ahmaruff/tada/internal/model"
+	"github.com/ahmaruff/tada/internal/processor"
+	"github.com/ahmaruff/tada/internal/writer"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import tasks written by other tools",
+	Long: `Import tasks and write them to the input file (-i, default input.md).
+
+Formats (--from):
+  json     a document written by tada export --format json
+  todotxt  a todo.txt file: every task goes to Backlog with a new ID
+           (--id-style), completed ones also to Done under their
+           completion date; +project sets the project and a priority
+           becomes a "Priority: A" description line
+  obsidian a note using Obsidian Tasks markers: tasks anywhere in the
+           note go to Backlog, indented ones become subtasks; 🛫/⏳/➕ set
+           the start date, ✅/❌ the end date, 🆔 the ID (others get one
+           with --id-style), the first #tag the project, and 📅 due dates
+           and priorities become description lines
+
+An existing input file is only replaced with --force.`,
+	Example: `  tada import tasks.json -i restored.md
+  tada import --from todotxt ~/todo/done.txt -i history.md --id-style project
+  tada import --from obsidian ~/vault/Work.md -i work.md`,
+	Args: cobra.ExactArgs(1),
+	Run:  runImport,
+}
+
+var (
+	importFrom    string
+	importIDStyle string
+	importForce   bool
+)
+
+func init() {
+	importCmd.Flags().StringVar(&importFrom, "from", "json", "Input format: json, todotxt or obsidian")
+	importCmd.Flags().StringVar(&importIDStyle, "id-style", "sequential", "todotxt, obsidian: ID style: sequential, project, hash or ulid")
+	importCmd.Flags().BoolVar(&importForce, "force", false, "Replace the input file if it exists")
+}
+
+func runImport(cmd *cobra.Command, args []string) {
+	outputFile := inputPath(nil)
+	startJournal(outputFile)
+
+	if _, err := os.Stat(outputFile); err == nil && !importForce {
+		log.Fatalf("%s already exists, use --force to replace it", outputFile)
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", args[0], err)
+	}
+	defer file.Close()
+
+	var sections []model.Section
+	switch importFrom {
+	case "json":
+		sections, err = exchange.ImportJSON(file)
+	case "todotxt", "obsidian":
+		style, styleErr := processor.ParseIDStyle(importIDStyle)
+		if styleErr != nil {
+			log.Fatalf("Invalid --id-style: %v", styleErr)
+		}
+		if importFrom == "todotxt" {
+			sections, err = exchange.ImportTodoTxt(file, style)
+		} else {
+			sections, err = exchange.ImportObsidian(file, style)
+		}
+	default:
+		log.Fatalf("Unknown format %q, expected json, todotxt or obsidian", importFrom)
+	}
+	if err != nil {
+		log.Fatalf("Failed to import %s: %v", args[0], err)
+	}
+
+	if err := writer.WriteInputFile(sections, outputFile); err != nil {
+		log.Fatalf("Failed to write %s: %v", outputFile, err)
+	}
+
+	var counts []string
+	for _, section := range sections {
+		counts = append(counts, fmt.Sprintf("%d %s", len(section.Tasks), section.Name))
+	}
+	fmt.Printf("Imported %s tasks into %s\n", strings.Join(counts, ", "), outputFile)
+}
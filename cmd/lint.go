@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ahmaruff/tada/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [file]",
+	Short: "Check the input file for problems",
+	Long: `Check the input file for malformed lines and unlinked tasks.
+
+Reports problems as file:line:column diagnostics:
+- duplicate task IDs in Backlog
+- Todo/Done tasks whose ID has no Backlog entry
+- unparseable dates and date ranges, ranges ending before they start
+- unknown status markers
+- subtasks before any task
+
+Exits with a non-zero status when errors are found, so it can be used as a
+pre-commit hook.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runLint,
+}
+
+var (
+	lintFormat string
+)
+
+func init() {
+	lintCmd.Flags().StringVar(&lintFormat, "format", "text", "Output format: text or json")
+}
+
+func runLint(cmd *cobra.Command, args []string) {
+	// Use positional argument if provided
+	inputFile := inputPath(args)
+
+	if lintFormat != "text" && lintFormat != "json" {
+		log.Fatalf("Unknown format %q, expected text or json", lintFormat)
+	}
+
+	diagnostics, err := parser.LintFile(inputFile)
+	if err != nil {
+		log.Fatalf("Failed to lint input file: %v", err)
+	}
+
+	var errorCount int
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == parser.SeverityError {
+			errorCount++
+		}
+	}
+
+	if lintFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(diagnostics); err != nil {
+			log.Fatalf("Failed to encode diagnostics: %v", err)
+		}
+	} else {
+		for _, diagnostic := range diagnostics {
+			fmt.Println(diagnostic)
+		}
+		if len(diagnostics) > 0 {
+			fmt.Printf("%d problems (%d errors, %d warnings)\n",
+				len(diagnostics), errorCount, len(diagnostics)-errorCount)
+		}
+	}
+
+	if errorCount > 0 {
+		os.Exit(1)
+	}
+}
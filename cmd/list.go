@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ahmaruff/tada/internal/model"
+	"github.com/ahmaruff/tada/internal/parser"
+	"github.com/ahmaruff/tada/internal/processor"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list [file]",
+	Short: "List tasks matching filters",
+	Long: `List tasks matching filters, without changing the input file.
+
+Tasks are consolidated in memory first, so Backlog shows the status
+recorded in Todo/Done even before running tidy. Only Backlog is listed
+unless --section is given; use --section all for every section.
+
+Filters can be combined. Comma-separated values match any of them.`,
+	Example: `  tada list --status in-progress -p backend
+  tada list --section done --from 2025-01-01 --to 2025-01-31
+  tada list --title login --format json`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runList,
+}
+
+var (
+	listStatuses []string
+	listProjects []string
+	listSections []string
+	listIDs      []string
+	listFrom     string
+	listTo       string
+	listTitle    string
+	listMatch    string
+	listSort     string
+	listFormat   string
+)
+
+func init() {
+	listCmd.Flags().StringSliceVar(&listStatuses, "status", nil, "Status: todo, in-progress or done")
+	listCmd.Flags().StringSliceVarP(&listProjects, "project", "p", nil, "Project name")
+	listCmd.Flags().StringSliceVarP(&listSections, "section", "s", []string{"backlog"}, "Section: backlog, todo, done, archives or all")
+	listCmd.Flags().StringSliceVar(&listIDs, "id", nil, "Task ID")
+	listCmd.Flags().StringVar(&listFrom, "from", "", "Only tasks active on or after this date (today or YYYY-MM-DD)")
+	listCmd.Flags().StringVar(&listTo, "to", "", "Only tasks active on or before this date (today or YYYY-MM-DD)")
+	listCmd.Flags().StringVarP(&listTitle, "title", "t", "", "Title contains this text (case-insensitive)")
+	listCmd.Flags().StringVar(&listMatch, "match", "", "Title matches this regular expression")
+	listCmd.Flags().StringVar(&listSort, "sort", "", "Sort by id, title, project, status, start or end (default file order)")
+	listCmd.Flags().StringVar(&listFormat, "format", "table", "Output format: table, plain or json")
+}
+
+func runList(cmd *cobra.Command, args []string) {
+	inputFile := inputPath(args)
+
+	filter, err := buildTaskFilter()
+	if err != nil {
+		log.Fatalf("Invalid filter: %v", err)
+	}
+
+	if listSort != "" && !validSortKey(listSort) {
+		log.Fatalf("Unknown sort key %q, expected id, title, project, status, start or end", listSort)
+	}
+
+	sections, err := parser.ParseFile(inputFile)
+	if err != nil {
+		log.Fatalf("Failed to parse input file: %v", err)
+	}
+
+	sections = processor.ConsolidateTasks(sections)
+
+	tasks := processor.FilterTasks(sections, filter)
+	processor.SortTasks(tasks, listSort)
+
+	switch listFormat {
+	case "table":
+		printTaskTable(tasks)
+	case "plain":
+		for _, task := range tasks {
+			fmt.Println(formatTaskLine(task))
+		}
+	case "json":
+		printTaskJSON(tasks)
+	default:
+		log.Fatalf("Unknown format %q, expected table, plain or json", listFormat)
+	}
+}
+
+func buildTaskFilter() (processor.TaskFilter, error) {
+	filter := processor.TaskFilter{
+		Projects: listProjects,
+		IDs:      trimIDs(listIDs),
+		Title:    listTitle,
+	}
+
+	for _, name := range listStatuses {
+		status, err := model.ParseStatusName(name)
+		if err != nil {
+			return filter, err
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+
+	for _, name := range listSections {
+		if strings.EqualFold(name, "all") {
+			filter.Sections = nil
+			break
+		}
+		section, err := parseSectionArg(name)
+		if err != nil {
+			return filter, err
+		}
+		filter.Sections = append(filter.Sections, section)
+	}
+
+	if listFrom != "" {
+		from, err := parseDateArg(listFrom)
+		if err != nil {
+			return filter, fmt.Errorf("--from: %w", err)
+		}
+		filter.From = &from
+	}
+
+	if listTo != "" {
+		to, err := parseDateArg(listTo)
+		if err != nil {
+			return filter, fmt.Errorf("--to: %w", err)
+		}
+		filter.To = &to
+	}
+
+	if listMatch != "" {
+		regex, err := regexp.Compile(listMatch)
+		if err != nil {
+			return filter, fmt.Errorf("--match: %w", err)
+		}
+		filter.TitleRegex = regex
+	}
+
+	return filter, nil
+}
+
+// parseSectionArg maps a section name given on the command line, in any
+// case, to a section.
+func parseSectionArg(name string) (model.SectionName, error) {
+	for _, section := range []model.SectionName{model.SectionBacklog, model.SectionTodo, model.SectionDone, model.SectionArchives} {
+		if strings.EqualFold(name, string(section)) || strings.EqualFold(name, section.Title()) {
+			return section, nil
+		}
+	}
+	return "", fmt.Errorf("unknown section %q, expected backlog, todo, done, archives or all", name)
+}
+
+func trimIDs(ids []string) []string {
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, strings.TrimPrefix(id, "#"))
+	}
+	return result
+}
+
+func validSortKey(key string) bool {
+	switch key {
+	case "id", "title", "project", "status", "start", "end":
+		return true
+	}
+	return false
+}
+
+func printTaskTable(tasks []model.Task) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tPROJECT\tSECTION\tDATES\tTITLE")
+	for _, task := range tasks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			task.ID, task.Status.Name(), task.Project, task.Position.Section,
+			model.FormatDateRange(task.StartDate, task.EndDate), task.Title)
+	}
+	w.Flush()
+}
+
+// formatTaskLine writes a task on one line, e.g. "[-] #42 @api Fix bug (2025-09-15)".
+func formatTaskLine(task model.Task) string {
+	parts := []string{string(task.Status)}
+	if task.ID != "" {
+		parts = append(parts, "#"+task.ID)
+	}
+	if task.Project != "" {
+		parts = append(parts, "@"+task.Project)
+	}
+	parts = append(parts, task.Title)
+	if dates := model.FormatDateRange(task.StartDate, task.EndDate); dates != "" {
+		parts = append(parts, "("+dates+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+type listedTask struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Project   string `json:"project"`
+	Status    string `json:"status"`
+	Section   string `json:"section"`
+	StartDate string `json:"start_date,omitempty"`
+	EndDate   string `json:"end_date,omitempty"`
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+}
+
+func printTaskJSON(tasks []model.Task) {
+	listed := make([]listedTask, 0, len(tasks))
+	for _, task := range tasks {
+		item := listedTask{
+			ID:      task.ID,
+			Title:   task.Title,
+			Project: task.Project,
+			Status:  task.Status.Name(),
+			Section: string(task.Position.Section),
+			File:    task.Position.File,
+			Line:    task.Position.StartLine,
+		}
+		if task.StartDate != nil {
+			item.StartDate = task.StartDate.Format("2006-01-02")
+		}
+		if task.EndDate != nil {
+			item.EndDate = task.EndDate.Format("2006-01-02")
+		}
+		listed = append(listed, item)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(listed); err != nil {
+		log.Fatalf("Failed to encode tasks: %v", err)
+	}
+}
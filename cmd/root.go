@@ -1,6 +1,18 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ahmaruff/tada/internal/config"
+	"github.com/ahmaruff/tada/internal/diff"
+	"github.com/ahmaruff/tada/internal/model"
+	"github.com/ahmaruff/tada/internal/parser"
+	"github.com/ahmaruff/tada/internal/writer"
+	"github.com/spf13/cobra"
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "tada",
@@ -8,15 +20,135 @@ var rootCmd = &cobra.Command{
 	Long: `tada is a CLI tool for managing tasks in markdown format.
 It consolidates task data across different sections and generates reports.
 
+Settings are read from .tada.yaml or .tada.toml in the current directory or
+any parent, and from $XDG_CONFIG_HOME/tada/config.yaml. Flags override them.
+
 Complete documentation is available at https://github.com/ahmaruff/tada`,
+	PersistentPreRunE: setupConfig,
+	SilenceUsage:      true,
+	SilenceErrors:     true,
 }
 
+var (
+	rootInputFile string
+	rootLocale    string
+
+	// cfg holds the effective settings for the running command
+	cfg config.Config
+)
+
 func Execute() error {
 	return rootCmd.Execute()
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVarP(&rootInputFile, "input", "i", "input.md", "Input markdown file")
+	rootCmd.PersistentFlags().StringVar(&rootLocale, "locale", "", "Day names for date headers: en, id, or 7 comma-separated names starting on Sunday (env TADA_LOCALE)")
+
 	// Add subcommands
 	rootCmd.AddCommand(genCmd)
 	rootCmd.AddCommand(tidyCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(addCmd)
+	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(doneCmd)
+	rootCmd.AddCommand(reopenCmd)
+	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(historyCmd)
+}
+
+// setupConfig loads the config files and fills in every flag the user did
+// not set on the command line.
+func setupConfig(cmd *cobra.Command, args []string) error {
+	loaded, err := config.Load(".")
+	if err != nil {
+		return err
+	}
+	cfg = loaded
+
+	configFlags := map[string]string{
+		"input":         cfg.Input,
+		"output":        cfg.Output,
+		"id-style":      cfg.IDStyle,
+		"template":      cfg.Template,
+		"group-by":      cfg.GroupBy,
+		"archive-dir":   cfg.ArchiveDir,
+		"archive-split": cfg.ArchiveSplit,
+	}
+	if cfg.Archive {
+		configFlags["archive"] = "true"
+	}
+	if cfg.KeepArchives {
+		configFlags["keep-archives"] = "true"
+	}
+	if cfg.AssignIDs {
+		configFlags["assign-ids"] = "true"
+	}
+	if len(cfg.ProjectOrder) > 0 {
+		configFlags["project-order"] = strings.Join(cfg.ProjectOrder, ",")
+	}
+
+	for name, value := range configFlags {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed || value == "" {
+			continue
+		}
+		if err := flag.Value.Set(value); err != nil {
+			return err
+		}
+	}
+
+	// Locale: flag, then TADA_LOCALE, then config
+	if cmd.Flags().Changed("locale") {
+		cfg.Locale = rootLocale
+	} else if env := os.Getenv("TADA_LOCALE"); env != "" {
+		cfg.Locale = env
+	}
+
+	locale, err := writer.ParseLocale(cfg.Locale)
+	if err != nil {
+		return err
+	}
+	writer.SetLocale(locale)
+
+	model.SetSectionTitles(cfg.Sections)
+	parser.SetObsidianTasks(cfg.ObsidianTasks)
+	writer.SetBackups(cfg.Backups)
+	return nil
+}
+
+// inputPath returns the input file given as positional argument, or the
+// --input flag.
+func inputPath(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return rootInputFile
+}
+
+// printDiff prints the unified diff from the current content of path to
+// content, coloured when stdout is a terminal and NO_COLOR is not set.
+func printDiff(path, content string) {
+	oldName := path
+	current, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		oldName = "/dev/null"
+	} else if err != nil {
+		log.Fatalf("Failed to read %s: %v", path, err)
+	}
+
+	changes := diff.Unified(oldName, path, string(current), content)
+	if changes == "" {
+		fmt.Printf("No changes to %s\n", path)
+		return
+	}
+
+	if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 && os.Getenv("NO_COLOR") == "" {
+		changes = diff.Colorize(changes)
+	}
+	fmt.Print(changes)
 }
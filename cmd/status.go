@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+	"github.com/ahmaruff/tada/internal/parser"
+	"github.com/ahmaruff/tada/internal/processor"
+	"github.com/ahmaruff/tada/internal/writer"
+	"github.com/spf13/cobra"
+)
+
+var startCmd = &cobra.Command{
+	Use:   "start <id>",
+	Short: "Mark a task as in progress",
+	Long: `Mark a Backlog task as in progress.
+
+The task is added to (or refreshed in) Todo under today's date header,
+then tasks are consolidated and the input file is rewritten once.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTransition(args[0], "Started", processor.StartTask)
+	},
+}
+
+var doneCmd = &cobra.Command{
+	Use:   "done <id>",
+	Short: "Mark a task as done",
+	Long: `Mark a Backlog task as done.
+
+The task is added to (or refreshed in) Done under today's date header and
+its end date is set, then tasks are consolidated and the input file is
+rewritten once.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTransition(args[0], "Completed", processor.CompleteTask)
+	},
+}
+
+var reopenCmd = &cobra.Command{
+	Use:   "reopen <id>",
+	Short: "Mark a done task as todo again",
+	Long: `Mark a done Backlog task as todo again.
+
+The task is added to Todo under today's date header with a "reopened"
+mark, so entries dated before it no longer decide its status, then tasks
+are consolidated and the input file is rewritten once. A completion on
+the same day is removed from Done; earlier entries are kept as they are.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTransition(args[0], "Reopened", processor.ReopenTask)
+	},
+}
+
+var statusDate string
+
+func init() {
+	for _, cmd := range []*cobra.Command{startCmd, doneCmd, reopenCmd} {
+		cmd.Flags().StringVar(&statusDate, "date", "today", "Date to record the change under (today or YYYY-MM-DD)")
+	}
+}
+
+type transition func(sections []model.Section, id string, day time.Time) ([]model.Section, error)
+
+func runTransition(id string, verb string, apply transition) {
+	inputFile := rootInputFile
+	startJournal(inputFile)
+	id = strings.TrimPrefix(id, "#")
+
+	day, err := parseDateArg(statusDate)
+	if err != nil {
+		log.Fatalf("Invalid --date: %v", err)
+	}
+
+	sections, err := parser.ParseFile(inputFile)
+	if err != nil {
+		log.Fatalf("Failed to parse input file: %v", err)
+	}
+
+	sections, err = apply(sections, id, day)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	sections = processor.ConsolidateTasks(sections)
+
+	err = writer.WriteInputFile(sections, inputFile)
+	if err != nil {
+		log.Fatalf("Failed to write updated input file: %v", err)
+	}
+
+	fmt.Printf("%s task #%s on %s in %s\n", verb, id, day.Format("2006-01-02"), inputFile)
+}
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"log"
+	"os"
+	"strings"
 
+	"github.com/ahmaruff/tada/internal/model"
 	"github.com/ahmaruff/tada/internal/parser"
 	"github.com/ahmaruff/tada/internal/processor"
 	"github.com/ahmaruff/tada/internal/writer"
@@ -21,30 +25,47 @@ This command:
 3. Optionally move completed Backlog tasks to Archives (--archive flag)
 4. Update input file
 
-Use --archive flag to move completed tasks from Backlog to Archives.`,
+Use --archive flag to move completed tasks from Backlog to Archives.
+
+Tasks without an #id are never synced. Use --assign-ids to give every
+Backlog task without an ID a new one (--id-style sequential, project, hash
+or ulid), and --link to link Todo/Done entries without an ID to the Backlog
+task with the most similar title, after confirmation.`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runTidy,
 }
 
 var (
-	tidyInputFile string
 	tidyArchive   bool
+	tidyAssignIDs bool
+	tidyIDStyle   string
+	tidyLink      bool
+	tidyYes       bool
 	tidyDryRun    bool
 	tidyVerbose   bool
 )
 
+// tidyLinkThreshold is the minimum title similarity offered by --link.
+const tidyLinkThreshold = 0.75
+
 func init() {
-	tidyCmd.Flags().StringVarP(&tidyInputFile, "input", "i", "input.md", "Input markdown file")
 	tidyCmd.Flags().BoolVarP(&tidyArchive, "archive", "a", false, "Move completed Backlog tasks to Archives")
+	tidyCmd.Flags().BoolVar(&tidyAssignIDs, "assign-ids", false, "Assign IDs to Backlog tasks without one")
+	tidyCmd.Flags().StringVar(&tidyIDStyle, "id-style", "sequential", "ID style for --assign-ids: sequential, project, hash or ulid")
+	tidyCmd.Flags().BoolVar(&tidyLink, "link", false, "Link Todo/Done tasks without ID to Backlog tasks with a similar title")
+	tidyCmd.Flags().BoolVarP(&tidyYes, "yes", "y", false, "Link without asking for confirmation")
 	tidyCmd.Flags().BoolVar(&tidyDryRun, "dry-run", false, "Preview changes without applying them")
 	tidyCmd.Flags().BoolVarP(&tidyVerbose, "verbose", "v", false, "Verbose output")
 }
 
 func runTidy(cmd *cobra.Command, args []string) {
 	// Use positional argument if provided
-	inputFile := tidyInputFile
-	if len(args) > 0 {
-		inputFile = args[0]
+	inputFile := inputPath(args)
+	startJournal(inputFile)
+
+	idStyle, err := processor.ParseIDStyle(tidyIDStyle)
+	if err != nil {
+		log.Fatalf("Invalid --id-style: %v", err)
 	}
 
 	if tidyVerbose {
@@ -70,13 +91,26 @@ func runTidy(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// Optionally assign IDs and link tasks without ID before consolidating
+	if tidyAssignIDs {
+		var assigned int
+		sections, assigned = processor.AssignIDs(sections, idStyle)
+		if tidyVerbose || tidyDryRun {
+			fmt.Printf("   Assigned %d task IDs (%s)\n", assigned, idStyle)
+		}
+	}
+
+	if tidyLink {
+		sections = linkTasksByTitle(sections)
+	}
+
 	// Count tasks before consolidation
 	var backlogBefore, completedBefore int
 	for _, section := range sections {
-		if section.Name == "Backlog" {
+		if section.Name == model.SectionBacklog {
 			backlogBefore = len(section.Tasks)
 			for _, task := range section.Tasks {
-				if task.Status == "StatusDone" {
+				if task.Status == model.StatusDone {
 					completedBefore++
 				}
 			}
@@ -93,10 +127,10 @@ func runTidy(cmd *cobra.Command, args []string) {
 	// Count tasks after consolidation
 	var backlogAfter, completedAfter int
 	for _, section := range sections {
-		if section.Name == "Backlog" {
+		if section.Name == model.SectionBacklog {
 			backlogAfter = len(section.Tasks)
 			for _, task := range section.Tasks {
-				if task.Status == "StatusDone" {
+				if task.Status == model.StatusDone {
 					completedAfter++
 				}
 			}
@@ -119,10 +153,10 @@ func runTidy(cmd *cobra.Command, args []string) {
 
 		// Count moved tasks
 		for _, section := range sections {
-			if section.Name == "Backlog" {
+			if section.Name == model.SectionBacklog {
 				finalCompleted := 0
 				for _, task := range section.Tasks {
-					if task.Status == "StatusDone" {
+					if task.Status == model.StatusDone {
 						finalCompleted++
 					}
 				}
@@ -141,6 +175,7 @@ func runTidy(cmd *cobra.Command, args []string) {
 		if tidyArchive && movedCount > 0 {
 			fmt.Printf("DRY RUN: Would move %d completed tasks to Archives\n", movedCount)
 		}
+		printDiff(inputFile, writer.GenerateInputMarkdown(sections))
 		return
 	}
 
@@ -165,3 +200,40 @@ func runTidy(cmd *cobra.Command, args []string) {
 		fmt.Printf(" in %s\n", inputFile)
 	}
 }
+
+// linkTasksByTitle offers each fuzzy title match for confirmation and links
+// the accepted ones.
+func linkTasksByTitle(sections []model.Section) []model.Section {
+	matches := processor.FindTitleMatches(sections, tidyLinkThreshold)
+	reader := bufio.NewReader(os.Stdin)
+
+	for _, match := range matches {
+		question := fmt.Sprintf("Link %s task %q to Backlog #%s %q (%.0f%% similar)?",
+			match.Section, match.Task.Title, match.Candidate.ID, match.Candidate.Title, match.Score*100)
+
+		if tidyDryRun {
+			fmt.Printf("DRY RUN: %s\n", question)
+			continue
+		}
+
+		if !tidyYes && !confirm(reader, question) {
+			continue
+		}
+
+		sections = processor.LinkTask(sections, match)
+		if tidyVerbose {
+			fmt.Printf("   Linked %q to #%s\n", match.Task.Title, match.Candidate.ID)
+		}
+	}
+
+	return sections
+}
+
+// confirm asks a yes/no question on stdout, defaulting to no.
+func confirm(reader *bufio.Reader, question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+
+	answer, _ := reader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/journal"
+	"github.com/ahmaruff/tada/internal/writer"
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [file]",
+	Short: "Revert the last command that changed files",
+	Long: `Revert the last command that changed the input file, as recorded in the
+journal in .tada next to it.
+
+Every file the command changed gets its previous content back, and files it
+created, such as a report written by gen, are removed. Run undo again to go
+further back. Undo refuses when a file was changed since, unless --force is
+given. Run tada history to see what would be undone.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runUndo,
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history [file]",
+	Short: "List the commands that changed files",
+	Long: `List the commands recorded in the journal in .tada next to the input file,
+newest first. The first one is reverted by tada undo.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runHistory,
+}
+
+var undoForce bool
+
+func init() {
+	undoCmd.Flags().BoolVar(&undoForce, "force", false, "Undo even if files were changed since")
+}
+
+// openJournal returns the journal kept next to the input file.
+func openJournal(inputFile string) journal.Journal {
+	return journal.Journal{
+		Dir:  filepath.Join(filepath.Dir(inputFile), ".tada"),
+		Keep: cfg.History,
+	}
+}
+
+// startJournal records every file the writer changes from now on as one
+// entry in the journal of inputFile, unless history is disabled.
+func startJournal(inputFile string) {
+	if cfg.History == 0 {
+		return
+	}
+
+	command := "tada"
+	for _, arg := range os.Args[1:] {
+		if strings.ContainsAny(arg, " \t\"'") {
+			arg = strconv.Quote(arg)
+		}
+		command += " " + arg
+	}
+	recorder := openJournal(inputFile).Recorder(command, time.Now())
+	writer.SetObserver(recorder.Record)
+}
+
+func runUndo(cmd *cobra.Command, args []string) {
+	entry, err := openJournal(inputPath(args)).Undo(undoForce)
+	if errors.Is(err, journal.ErrEmpty) {
+		fmt.Println("Nothing to undo")
+		return
+	}
+	if err != nil {
+		log.Fatalf("Failed to undo: %v", err)
+	}
+
+	fmt.Printf("Undid %q from %s\n", entry.Command, entry.Time.Local().Format("2006-01-02 15:04:05"))
+	for _, change := range entry.Changes {
+		if change.Before == "" {
+			fmt.Printf("  removed %s\n", change.Path)
+		} else {
+			fmt.Printf("  restored %s\n", change.Path)
+		}
+	}
+}
+
+func runHistory(cmd *cobra.Command, args []string) {
+	entries, err := openJournal(inputPath(args)).Entries()
+	if err != nil {
+		log.Fatalf("Failed to read journal: %v", err)
+	}
+	if len(entries) == 0 {
+		fmt.Println("No history")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tCOMMAND\tFILES")
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		var files []string
+		for _, change := range entry.Changes {
+			name := filepath.Base(change.Path)
+			if change.Before == "" {
+				name += " (new)"
+			}
+			files = append(files, name)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Command, strings.Join(files, ", "))
+	}
+	w.Flush()
+}
@@ -0,0 +1,171 @@
+package archive
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+	"github.com/ahmaruff/tada/internal/parser"
+	"github.com/ahmaruff/tada/internal/writer"
+)
+
+// Split selects how the store spreads tasks across files.
+type Split string
+
+const (
+	SplitYear  Split = "year"  // archive/2025.md
+	SplitMonth Split = "month" // archive/2025-09.md
+)
+
+// ParseSplit checks an archive split name.
+func ParseSplit(value string) (Split, error) {
+	switch split := Split(strings.ToLower(value)); split {
+	case SplitYear, SplitMonth:
+		return split, nil
+	case "":
+		return SplitYear, nil
+	default:
+		return "", fmt.Errorf("unknown archive split %q, expected year or month", value)
+	}
+}
+
+// Store is a directory of markdown files holding archived tasks, one file
+// per year or month. Each file has a single Archives section in the input
+// file format, so it can be read and edited like any tada file.
+type Store struct {
+	Dir   string
+	Split Split
+}
+
+// Path returns the file holding tasks completed on date.
+func (s Store) Path(date time.Time) string {
+	name := date.Format("2006")
+	if s.Split == SplitMonth {
+		name = date.Format("2006-01")
+	}
+	return filepath.Join(s.Dir, name+".md")
+}
+
+// Append adds tasks to the files matching their completion date, skipping
+// tasks already stored. Tasks without a date are filed under now. It returns
+// the number of tasks appended per file.
+func (s Store) Append(tasks []model.Task, now time.Time) (map[string]int, error) {
+	byPath := make(map[string][]model.Task)
+	var paths []string
+	for _, task := range tasks {
+		path := s.Path(completedDate(task, now))
+		if _, ok := byPath[path]; !ok {
+			paths = append(paths, path)
+		}
+		byPath[path] = append(byPath[path], task)
+	}
+
+	if len(paths) > 0 {
+		if err := os.MkdirAll(s.Dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create archive directory %s: %w", s.Dir, err)
+		}
+	}
+
+	appended := make(map[string]int)
+	for _, path := range paths {
+		count, err := appendToFile(path, byPath[path])
+		if err != nil {
+			return appended, err
+		}
+		if count > 0 {
+			appended[path] = count
+		}
+	}
+
+	return appended, nil
+}
+
+// Load returns every task in the store, oldest file first.
+func (s Store) Load() ([]model.Task, error) {
+	paths, err := filepath.Glob(filepath.Join(s.Dir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var tasks []model.Task
+	for _, path := range paths {
+		sections, err := parser.ParseFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, section := range sections {
+			if section.Name == model.SectionArchives {
+				tasks = append(tasks, section.Tasks...)
+			}
+		}
+	}
+
+	return tasks, nil
+}
+
+func appendToFile(path string, tasks []model.Task) (int, error) {
+	var sections []model.Section
+	if _, err := os.Stat(path); err == nil {
+		if sections, err = parser.ParseFile(path); err != nil {
+			return 0, err
+		}
+	}
+
+	index := -1
+	stored := make(map[string]bool)
+	for i, section := range sections {
+		if section.Name == model.SectionArchives {
+			index = i
+			for _, task := range section.Tasks {
+				stored[task.Fingerprint()] = true
+			}
+		}
+	}
+	if index < 0 {
+		sections = append(sections, model.Section{Name: model.SectionArchives})
+		index = len(sections) - 1
+	}
+
+	count := 0
+	for _, task := range tasks {
+		// A single date reads back as both start and end date
+		if task.EndDate == nil {
+			task.EndDate = task.StartDate
+		}
+		if stored[task.Fingerprint()] {
+			continue
+		}
+		stored[task.Fingerprint()] = true
+
+		// Render the task afresh rather than copying its lines, and any
+		// notes after them, from the input file
+		task.Source = nil
+		task.Position = model.Position{}
+		sections[index].Tasks = append(sections[index].Tasks, task)
+		count++
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+	if err := writer.WriteInputFile(sections, path); err != nil {
+		return 0, fmt.Errorf("failed to write archive file %s: %w", path, err)
+	}
+	return count, nil
+}
+
+func completedDate(task model.Task, now time.Time) time.Time {
+	switch {
+	case task.EndDate != nil:
+		return *task.EndDate
+	case task.StartDate != nil:
+		return *task.StartDate
+	default:
+		return now
+	}
+}
@@ -0,0 +1,112 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+)
+
+func date(year int, month time.Month, day int) *time.Time {
+	d := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &d
+}
+
+func TestParseSplit(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Split
+		wantErr  bool
+	}{
+		{"", SplitYear, false},
+		{"year", SplitYear, false},
+		{"Month", SplitMonth, false},
+		{"week", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			split, err := ParseSplit(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if split != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, split)
+			}
+		})
+	}
+}
+
+func TestStoreAppendAndLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "archive")
+	store := Store{Dir: dir, Split: SplitMonth}
+	now := time.Date(2025, 10, 2, 0, 0, 0, 0, time.UTC)
+
+	tasks := []model.Task{
+		{ID: "1", Title: "Parser", Project: "tada", Status: model.StatusDone,
+			StartDate: date(2025, 8, 30), EndDate: date(2025, 9, 2), Description: []string{"lexer"},
+			Source: &model.Source{Lines: []string{"- [x] stale raw line"}, Trailing: []string{"note"}}},
+		{ID: "2", Title: "Writer", Status: model.StatusDone, StartDate: date(2025, 9, 13)},
+		{ID: "3", Title: "Undated", Status: model.StatusDone},
+	}
+
+	appended, err := store.Append(tasks, now)
+	if err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	september := filepath.Join(dir, "2025-09.md")
+	october := filepath.Join(dir, "2025-10.md")
+	if appended[september] != 2 || appended[october] != 1 || len(appended) != 2 {
+		t.Errorf("Expected 2 tasks in 2025-09.md and 1 in 2025-10.md, got %v", appended)
+	}
+
+	content, err := os.ReadFile(september)
+	if err != nil {
+		t.Fatalf("Failed to read archive file: %v", err)
+	}
+	expected := "## Archives\n" +
+		"- [x] Parser <!-- @tada|#1|2025-08-30 - 2025-09-02 -->\n" +
+		"  lexer\n" +
+		"- [x] Writer <!-- #2|2025-09-13 -->\n"
+	if string(content) != expected {
+		t.Errorf("Expected archive file:\n%s\ngot:\n%s", expected, content)
+	}
+
+	// Appending again only adds tasks that are not stored yet
+	tasks = append(tasks, model.Task{ID: "4", Title: "Docs", Status: model.StatusDone, EndDate: date(2025, 9, 20)})
+	appended, err = store.Append(tasks, now)
+	if err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if appended[september] != 1 || len(appended) != 1 {
+		t.Errorf("Expected only the new task to be appended, got %v", appended)
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	var ids []string
+	for _, task := range loaded {
+		ids = append(ids, task.ID)
+	}
+	if strings.Join(ids, ",") != "1,2,4,3" {
+		t.Errorf("Expected tasks 1,2,4,3, got %v", ids)
+	}
+}
+
+func TestStorePath(t *testing.T) {
+	day := time.Date(2025, 9, 13, 0, 0, 0, 0, time.UTC)
+
+	if path := (Store{Dir: "archive", Split: SplitYear}).Path(day); path != filepath.Join("archive", "2025.md") {
+		t.Errorf("Expected archive/2025.md, got %s", path)
+	}
+	if path := (Store{Dir: "archive", Split: SplitMonth}).Path(day); path != filepath.Join("archive", "2025-09.md") {
+		t.Errorf("Expected archive/2025-09.md, got %s", path)
+	}
+}
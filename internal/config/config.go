@@ -0,0 +1,309 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/journal"
+	"github.com/ahmaruff/tada/internal/model"
+	"github.com/ahmaruff/tada/internal/writer"
+)
+
+// FileNames are the project config files looked up from the working
+// directory upwards, in order of preference.
+var FileNames = []string{".tada.yaml", ".tada.yml", ".tada.toml"}
+
+// userFileNames are looked up in $XDG_CONFIG_HOME/tada.
+var userFileNames = []string{"config.yaml", "config.yml", "config.toml"}
+
+// Config holds the settings shared by all commands.
+type Config struct {
+	Input         string                       // input markdown file
+	Output        string                       // report directory
+	Filename      string                       // report filename pattern
+	Template      string                       // report template file, empty for the built-in one
+	GroupBy       string                       // report grouping: project, date, status or empty
+	ProjectOrder  []string                     // project order in reports grouped by project
+	SprintStart   string                       // first day of sprint 1, YYYY-MM-DD
+	SprintDays    int                          // sprint length in days
+	Locale        string                       // day names for date headers
+	Archive       bool                         // tidy moves completed tasks to Archives
+	KeepArchives  bool                         // gen leaves Archives in the input file
+	ArchiveDir    string                       // archive store directory, empty to disable
+	ArchiveSplit  string                       // one archive file per year or month
+	AssignIDs     bool                         // tidy assigns IDs to Backlog tasks without one
+	IDStyle       string                       // sequential, project, hash or ulid
+	ObsidianTasks bool                         // read Obsidian Tasks emoji markers in task titles
+	Backups       int                          // backups kept per task file, 0 to disable
+	History       int                          // journal entries kept for tada undo, 0 to disable
+	Sections      map[model.SectionName]string // "## " header text per section
+
+	Files []string // config files that were loaded, lowest precedence first
+}
+
+// Default returns the built-in settings.
+func Default() Config {
+	return Config{
+		Input:        "input.md",
+		Output:       ".",
+		Filename:     "report_{start}_{end}.md",
+		Locale:       writer.DefaultLocale,
+		IDStyle:      "sequential",
+		ArchiveSplit: "year",
+		SprintDays:   14,
+		Backups:      writer.DefaultBackups,
+		History:      journal.DefaultKeep,
+		Sections:     map[model.SectionName]string{},
+	}
+}
+
+// Load returns the effective config for a command run from dir: the
+// defaults, overlaid by the user config in $XDG_CONFIG_HOME/tada, overlaid
+// by the nearest project config found walking up from dir.
+func Load(dir string) (Config, error) {
+	cfg := Default()
+
+	if path := findUserFile(); path != "" {
+		if err := LoadFile(path, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+
+	if path := findProjectFile(dir); path != "" {
+		if err := LoadFile(path, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// LoadFile reads a YAML or TOML config file into cfg, keeping the values it
+// does not set. Relative paths are resolved against the file's directory.
+func LoadFile(path string, cfg *Config) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file %s: %w", path, err)
+	}
+
+	defer file.Close()
+
+	var values map[string]string
+	scanner := bufio.NewScanner(file)
+	if strings.HasSuffix(path, ".toml") {
+		values, err = parseTOML(scanner)
+	} else {
+		values, err = parseYAML(scanner)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if err := cfg.apply(values, filepath.Dir(path)); err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	cfg.Files = append(cfg.Files, path)
+	return nil
+}
+
+// Values returns the settings as flat "key: value" pairs in a stable order,
+// using the same keys as the config file.
+func (c Config) Values() [][2]string {
+	values := [][2]string{
+		{"input", c.Input},
+		{"output", c.Output},
+		{"filename", c.Filename},
+		{"template", c.Template},
+		{"group_by", c.GroupBy},
+		{"project_order", strings.Join(c.ProjectOrder, ", ")},
+		{"sprint_start", c.SprintStart},
+		{"sprint_days", strconv.Itoa(c.SprintDays)},
+		{"locale", c.Locale},
+		{"archive", strconv.FormatBool(c.Archive)},
+		{"keep_archives", strconv.FormatBool(c.KeepArchives)},
+		{"archive_dir", c.ArchiveDir},
+		{"archive_split", c.ArchiveSplit},
+		{"assign_ids", strconv.FormatBool(c.AssignIDs)},
+		{"id_style", c.IDStyle},
+		{"obsidian_tasks", strconv.FormatBool(c.ObsidianTasks)},
+		{"backups", strconv.Itoa(c.Backups)},
+		{"history", strconv.Itoa(c.History)},
+	}
+
+	for _, name := range []model.SectionName{model.SectionBacklog, model.SectionTodo, model.SectionDone, model.SectionArchives} {
+		title := c.Sections[name]
+		if title == "" {
+			title = string(name)
+		}
+		values = append(values, [2]string{"sections." + strings.ToLower(string(name)), title})
+	}
+
+	return values
+}
+
+func (c *Config) apply(values map[string]string, dir string) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := values[key]
+
+		switch key {
+		case "input":
+			c.Input = resolvePath(dir, value)
+		case "output":
+			c.Output = resolvePath(dir, value)
+		case "filename":
+			c.Filename = value
+		case "template":
+			c.Template = resolvePath(dir, value)
+		case "group_by":
+			c.GroupBy = value
+		case "project_order":
+			c.ProjectOrder = nil
+			for _, project := range strings.Split(value, ",") {
+				if project = strings.TrimSpace(project); project != "" {
+					c.ProjectOrder = append(c.ProjectOrder, project)
+				}
+			}
+		case "sprint_start":
+			if _, err := time.Parse("2006-01-02", value); err != nil {
+				return fmt.Errorf("sprint_start: expected YYYY-MM-DD, got %q", value)
+			}
+			c.SprintStart = value
+		case "sprint_days":
+			days, err := strconv.Atoi(value)
+			if err != nil || days < 1 {
+				return fmt.Errorf("sprint_days: expected a positive number, got %q", value)
+			}
+			c.SprintDays = days
+		case "locale":
+			c.Locale = value
+		case "archive":
+			archive, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("archive: expected true or false, got %q", value)
+			}
+			c.Archive = archive
+		case "keep_archives":
+			keep, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("keep_archives: expected true or false, got %q", value)
+			}
+			c.KeepArchives = keep
+		case "archive_dir":
+			c.ArchiveDir = resolvePath(dir, value)
+		case "archive_split":
+			c.ArchiveSplit = value
+		case "assign_ids":
+			assign, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("assign_ids: expected true or false, got %q", value)
+			}
+			c.AssignIDs = assign
+		case "id_style":
+			c.IDStyle = value
+		case "obsidian_tasks":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("obsidian_tasks: expected true or false, got %q", value)
+			}
+			c.ObsidianTasks = enabled
+		case "backups":
+			keep, err := strconv.Atoi(value)
+			if err != nil || keep < 0 {
+				return fmt.Errorf("backups: expected a number, got %q", value)
+			}
+			c.Backups = keep
+		case "history":
+			keep, err := strconv.Atoi(value)
+			if err != nil || keep < 0 {
+				return fmt.Errorf("history: expected a number, got %q", value)
+			}
+			c.History = keep
+		case "sections.backlog":
+			c.Sections[model.SectionBacklog] = value
+		case "sections.todo":
+			c.Sections[model.SectionTodo] = value
+		case "sections.done":
+			c.Sections[model.SectionDone] = value
+		case "sections.archives":
+			c.Sections[model.SectionArchives] = value
+		default:
+			return fmt.Errorf("unknown setting %q", key)
+		}
+	}
+
+	// Each section needs its own header to be recognised when parsing
+	titles := make(map[string]model.SectionName)
+	for _, name := range []model.SectionName{model.SectionBacklog, model.SectionTodo, model.SectionDone, model.SectionArchives} {
+		title := c.Sections[name]
+		if title == "" {
+			title = string(name)
+		}
+		if other, ok := titles[title]; ok {
+			return fmt.Errorf("sections.%s: title %q is already used by %s", strings.ToLower(string(name)), title, other)
+		}
+		titles[title] = name
+	}
+
+	return nil
+}
+
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+func findProjectFile(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		for _, name := range FileNames {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func findUserFile() string {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(home, ".config")
+	}
+
+	for _, name := range userFileNames {
+		path := filepath.Join(base, "tada", name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ahmaruff/tada/internal/model"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestLoadFileYAML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".tada.yaml")
+	writeFile(t, path, `# project settings
+input: tasks.md
+output: reports   # relative to this file
+filename: "weekly_{start}.md"
+template: templates/weekly.tmpl
+project_order: [tada, "crm app"]   # listed first
+locale: 'en'
+archive: true
+archive_dir: history
+sections:
+  backlog: Inbox
+  archives: History
+`)
+
+	cfg := Default()
+	if err := LoadFile(path, &cfg); err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+
+	if cfg.Input != filepath.Join(dir, "tasks.md") {
+		t.Errorf("Expected input to be resolved against the config dir, got '%s'", cfg.Input)
+	}
+	if cfg.Output != filepath.Join(dir, "reports") {
+		t.Errorf("Expected output to be resolved against the config dir, got '%s'", cfg.Output)
+	}
+	if cfg.Filename != "weekly_{start}.md" {
+		t.Errorf("Expected filename 'weekly_{start}.md', got '%s'", cfg.Filename)
+	}
+	if cfg.Template != filepath.Join(dir, "templates", "weekly.tmpl") {
+		t.Errorf("Expected template to be resolved against the config dir, got '%s'", cfg.Template)
+	}
+	if strings.Join(cfg.ProjectOrder, "|") != "tada|crm app" {
+		t.Errorf("Expected project order [tada crm app], got %v", cfg.ProjectOrder)
+	}
+	if cfg.Locale != "en" {
+		t.Errorf("Expected locale 'en', got '%s'", cfg.Locale)
+	}
+	if !cfg.Archive {
+		t.Errorf("Expected archive to be true")
+	}
+	if cfg.ArchiveDir != filepath.Join(dir, "history") || cfg.ArchiveSplit != "year" {
+		t.Errorf("Expected archive store in %s split by year, got %s by %s", filepath.Join(dir, "history"), cfg.ArchiveDir, cfg.ArchiveSplit)
+	}
+	if cfg.Sections[model.SectionBacklog] != "Inbox" || cfg.Sections[model.SectionArchives] != "History" {
+		t.Errorf("Unexpected section names %v", cfg.Sections)
+	}
+}
+
+func TestLoadFileTOML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".tada.toml")
+	writeFile(t, path, `input = "/abs/tasks.md" # comment
+archive = false
+group_by = "project"
+sprint_start = "2025-01-06"
+sprint_days = 7
+obsidian_tasks = true
+backups = 0
+history = 5
+project_order = "crm, tada"
+
+[sections]
+todo = "Today \"focus\"" # escaped quotes
+`)
+
+	cfg := Default()
+	if err := LoadFile(path, &cfg); err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+
+	if cfg.Input != "/abs/tasks.md" {
+		t.Errorf("Expected absolute input to be kept, got '%s'", cfg.Input)
+	}
+	if cfg.Output != "." {
+		t.Errorf("Expected default output to be kept, got '%s'", cfg.Output)
+	}
+	if cfg.GroupBy != "project" || strings.Join(cfg.ProjectOrder, "|") != "crm|tada" {
+		t.Errorf("Expected grouping by project in order crm, tada, got %s %v", cfg.GroupBy, cfg.ProjectOrder)
+	}
+	if cfg.SprintStart != "2025-01-06" || cfg.SprintDays != 7 {
+		t.Errorf("Expected one-week sprints from 2025-01-06, got %d days from %s", cfg.SprintDays, cfg.SprintStart)
+	}
+	if !cfg.ObsidianTasks {
+		t.Errorf("Expected obsidian_tasks to be true")
+	}
+	if cfg.Backups != 0 || cfg.History != 5 {
+		t.Errorf("Expected no backups and 5 journal entries, got %d and %d", cfg.Backups, cfg.History)
+	}
+	if cfg.Sections[model.SectionTodo] != `Today "focus"` {
+		t.Errorf("Expected todo section 'Today \"focus\"', got '%s'", cfg.Sections[model.SectionTodo])
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"unknown key", ".tada.yaml", "colour: red\n"},
+		{"bad bool", ".tada.yaml", "archive: maybe\n"},
+		{"bad keep_archives", ".tada.toml", "keep_archives = 1.5\n"},
+		{"list", ".tada.yaml", "sections:\n  - Backlog\n"},
+		{"missing equals", ".tada.toml", "input\n"},
+		{"unterminated list", ".tada.toml", "project_order = [a, b\n"},
+		{"bad sprint_start", ".tada.yaml", "sprint_start: next monday\n"},
+		{"bad sprint_days", ".tada.yaml", "sprint_days: 0\n"},
+		{"bad obsidian_tasks", ".tada.yaml", "obsidian_tasks: yes\n"},
+		{"bad backups", ".tada.yaml", "backups: -1\n"},
+		{"bad history", ".tada.yaml", "history: all\n"},
+		{"duplicate section title", ".tada.yaml", "sections:\n  todo: Work\n  done: Work\n"},
+		{"default section title", ".tada.yaml", "sections:\n  todo: Backlog\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			writeFile(t, path, tt.content)
+
+			cfg := Default()
+			if err := LoadFile(path, &cfg); err == nil {
+				t.Errorf("Expected error for %q", tt.content)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+
+	writeFile(t, filepath.Join(root, "xdg", "tada", "config.yaml"), "locale: en\noutput: /reports\n")
+	writeFile(t, filepath.Join(root, "project", ".tada.yaml"), "output: out\n")
+
+	dir := filepath.Join(root, "project", "nested", "dir")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(cfg.Files) != 2 {
+		t.Fatalf("Expected 2 config files, got %v", cfg.Files)
+	}
+	if cfg.Locale != "en" {
+		t.Errorf("Expected locale from user config, got '%s'", cfg.Locale)
+	}
+	if cfg.Output != filepath.Join(root, "project", "out") {
+		t.Errorf("Expected project config to override output, got '%s'", cfg.Output)
+	}
+	if cfg.Input != "input.md" {
+		t.Errorf("Expected default input, got '%s'", cfg.Input)
+	}
+}
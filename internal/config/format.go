@@ -0,0 +1,149 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The config files only need flat settings and one level of nesting, so
+// both formats are read with a small subset parser into dotted keys, e.g.
+// "sections.backlog".
+
+// parseYAML reads "key: value" pairs, where a key with no value starts a
+// block of indented pairs.
+func parseYAML(scanner *bufio.Scanner) (map[string]string, error) {
+	values := make(map[string]string)
+	parent := ""
+	lineNo := 0
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNo++
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "- ") {
+			return nil, fmt.Errorf("line %d: lists are not supported", lineNo)
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", lineNo)
+		}
+		key = strings.TrimSpace(key)
+
+		value, err := parseValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+
+		indented := len(line) > len(strings.TrimLeft(line, " \t"))
+		switch {
+		case !indented && value == "":
+			parent = key
+		case !indented:
+			parent = ""
+			values[key] = value
+		case parent == "":
+			return nil, fmt.Errorf("line %d: unexpected indentation", lineNo)
+		default:
+			values[parent+"."+key] = value
+		}
+	}
+
+	return values, scanner.Err()
+}
+
+// parseTOML reads "key = value" pairs and "[table]" headers.
+func parseTOML(scanner *bufio.Scanner) (map[string]string, error) {
+	values := make(map[string]string)
+	table := ""
+	lineNo := 0
+
+	for scanner.Scan() {
+		lineNo++
+
+		trimmed := strings.TrimSpace(scanner.Text())
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[") {
+			end := strings.Index(trimmed, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated table header", lineNo)
+			}
+			table = strings.TrimSpace(trimmed[1:end])
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key = value\"", lineNo)
+		}
+		key = strings.TrimSpace(key)
+
+		value, err := parseValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+
+		if table != "" {
+			key = table + "." + key
+		}
+		values[key] = value
+	}
+
+	return values, scanner.Err()
+}
+
+// parseValue unquotes a scalar value and drops a trailing comment. An
+// inline list such as [a, "b"] becomes the comma-separated string "a,b".
+func parseValue(value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	if strings.HasPrefix(value, "[") {
+		end := strings.Index(value, "]")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated list %s", value)
+		}
+
+		var items []string
+		for _, item := range strings.Split(value[1:end], ",") {
+			if strings.TrimSpace(item) == "" {
+				continue
+			}
+			item, err := parseValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, item)
+		}
+		return strings.Join(items, ","), nil
+	}
+
+	if strings.HasPrefix(value, `"`) {
+		quoted, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			return "", fmt.Errorf("unterminated string %s", value)
+		}
+		return strconv.Unquote(quoted)
+	}
+
+	if strings.HasPrefix(value, "'") {
+		end := strings.Index(value[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string %s", value)
+		}
+		return value[1 : end+1], nil
+	}
+
+	if comment := strings.Index(value, " #"); comment >= 0 {
+		value = value[:comment]
+	}
+	return strings.TrimSpace(value), nil
+}
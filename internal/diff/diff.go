@@ -0,0 +1,293 @@
+// Package diff renders line-based unified diffs, to preview file changes.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Context is the number of unchanged lines shown around each change.
+const Context = 3
+
+// ANSI colours used by Colorize.
+const (
+	colorReset = "\x1b[0m"
+	colorBold  = "\x1b[1m"
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorCyan  = "\x1b[36m"
+)
+
+type op struct {
+	kind    byte // ' ', '-' or '+'
+	line    string
+	oldLine int // 1-based line in the old text, for ' ' and '-'
+	newLine int // 1-based line in the new text, for ' ' and '+'
+}
+
+// Unified returns the unified diff turning oldText into newText, with the
+// given file names in the header, or "" when they are equal. Use /dev/null
+// as oldName for a file that does not exist yet.
+func Unified(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	ops := lineOps(splitLines(oldText), splitLines(newText))
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "--- %s\n+++ %s\n", oldName, newName)
+	for _, hunk := range hunks(ops) {
+		writeHunk(&result, ops[hunk[0]:hunk[1]])
+	}
+	return result.String()
+}
+
+// Colorize colours a unified diff for a terminal: headers in bold, hunk
+// ranges in cyan, removed lines in red and added lines in green.
+func Colorize(diff string) string {
+	var result strings.Builder
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if line == "" {
+			continue
+		}
+		text := strings.TrimSuffix(line, "\n")
+
+		color := ""
+		switch {
+		case strings.HasPrefix(text, "--- "), strings.HasPrefix(text, "+++ "):
+			color = colorBold
+		case strings.HasPrefix(text, "@@"):
+			color = colorCyan
+		case strings.HasPrefix(text, "-"):
+			color = colorRed
+		case strings.HasPrefix(text, "+"):
+			color = colorGreen
+		}
+
+		if color == "" {
+			result.WriteString(line)
+			continue
+		}
+		result.WriteString(color + text + colorReset + strings.TrimPrefix(line, text))
+	}
+	return result.String()
+}
+
+// splitLines splits text after each newline; a last line without one is
+// kept as is, so it differs from the same line with a newline.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// lineOps computes a shortest edit script from a to b, listing removals
+// before additions.
+func lineOps(a, b []string) []op {
+	var matches [][2]int
+	matchLines(a, b, 0, len(a), 0, len(b), &matches)
+
+	var ops []op
+	i, j := 0, 0
+	for k := 0; k <= len(matches); k++ {
+		// Lines up to the next match, or to the end, were removed or added
+		nextI, nextJ := len(a), len(b)
+		if k < len(matches) {
+			nextI, nextJ = matches[k][0], matches[k][1]
+		}
+		for ; i < nextI; i++ {
+			ops = append(ops, op{kind: '-', line: a[i], oldLine: i + 1, newLine: j})
+		}
+		for ; j < nextJ; j++ {
+			ops = append(ops, op{kind: '+', line: b[j], oldLine: i, newLine: j + 1})
+		}
+
+		if k < len(matches) {
+			ops = append(ops, op{kind: ' ', line: a[i], oldLine: i + 1, newLine: j + 1})
+			i++
+			j++
+		}
+	}
+	return ops
+}
+
+// matchLines appends to matches, in order, the pairs of equal lines kept by
+// a shortest edit script from a[aLo:aHi] to b[bLo:bHi]. It splits the
+// problem on the middle snake of Myers' algorithm, so it needs memory
+// linear in the length of the texts rather than quadratic.
+func matchLines(a, b []string, aLo, aHi, bLo, bHi int, matches *[][2]int) {
+	// Common lines at either end need no search
+	for aLo < aHi && bLo < bHi && a[aLo] == b[bLo] {
+		*matches = append(*matches, [2]int{aLo, bLo})
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi && bLo < bHi && a[aHi-1] == b[bHi-1] {
+		aHi--
+		bHi--
+		suffix++
+	}
+
+	if aLo < aHi && bLo < bHi {
+		if x, y, ok := middleSnake(a, b, aLo, aHi, bLo, bHi); ok {
+			matchLines(a, b, aLo, x, bLo, y, matches)
+			matchLines(a, b, x, aHi, y, bHi, matches)
+		}
+	}
+
+	for k := 0; k < suffix; k++ {
+		*matches = append(*matches, [2]int{aHi + k, bHi + k})
+	}
+}
+
+// middleSnake searches forward from the start and backward from the end of
+// a[aLo:aHi] and b[bLo:bHi] until the paths overlap, and returns the point
+// where they meet. It returns false when the ranges have no line in common.
+func middleSnake(a, b []string, aLo, aHi, bLo, bHi int) (int, int, bool) {
+	n, m := aHi-aLo, bHi-bLo
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+
+	// forward[offset+k] is the furthest x reached on diagonal k = x - y from
+	// the start, backward[offset+k] the same counted from the end
+	forward := make([]int, 2*maxD+3)
+	backward := make([]int, 2*maxD+3)
+	for i := range forward {
+		forward[i] = -1
+		backward[i] = -1
+	}
+	forward[offset+1] = 0
+	backward[offset+1] = 0
+
+	delta := n - m
+	odd := delta%2 != 0
+	// Diagonals that ran off an edge of the grid are skipped
+	kfStart, kfEnd, kbStart, kbEnd := 0, 0, 0, 0
+
+	for d := 0; d < maxD; d++ {
+		for k := -d + kfStart; k <= d-kfEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[aLo+x] == b[bLo+y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			switch {
+			case x > n:
+				kfEnd += 2
+			case y > m:
+				kfStart += 2
+			case odd:
+				kb := offset + delta - k
+				if kb >= 0 && kb < len(backward) && backward[kb] != -1 && x >= n-backward[kb] {
+					return aLo + x, bLo + y, true
+				}
+			}
+		}
+
+		for k := -d + kbStart; k <= d-kbEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[aHi-x-1] == b[bHi-y-1] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+
+			switch {
+			case x > n:
+				kbEnd += 2
+			case y > m:
+				kbStart += 2
+			case !odd:
+				kf := offset + delta - k
+				if kf >= 0 && kf < len(forward) && forward[kf] != -1 {
+					fx := forward[kf]
+					fy := fx - (kf - offset)
+					if fx >= n-x {
+						return aLo + fx, bLo + fy, true
+					}
+				}
+			}
+		}
+	}
+
+	return 0, 0, false
+}
+
+// hunks returns the [start, end) ranges of ops to print: every change with
+// Context unchanged lines around it, merging ranges that touch.
+func hunks(ops []op) [][2]int {
+	var ranges [][2]int
+	for i, o := range ops {
+		if o.kind == ' ' {
+			continue
+		}
+
+		start, end := max(i-Context, 0), min(i+1+Context, len(ops))
+		if n := len(ranges); n > 0 && start <= ranges[n-1][1] {
+			ranges[n-1][1] = end
+			continue
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+	return ranges
+}
+
+func writeHunk(result *strings.Builder, ops []op) {
+	oldStart, oldCount, newStart, newCount := 0, 0, 0, 0
+	for _, o := range ops {
+		if o.kind != '+' {
+			if oldCount == 0 {
+				oldStart = o.oldLine
+			}
+			oldCount++
+		}
+		if o.kind != '-' {
+			if newCount == 0 {
+				newStart = o.newLine
+			}
+			newCount++
+		}
+	}
+
+	// An empty range starts at the line before it
+	if oldCount == 0 {
+		oldStart = ops[0].oldLine
+	}
+	if newCount == 0 {
+		newStart = ops[0].newLine
+	}
+
+	fmt.Fprintf(result, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+	for _, o := range ops {
+		result.WriteByte(o.kind)
+		result.WriteString(o.line)
+		if !strings.HasSuffix(o.line, "\n") {
+			result.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
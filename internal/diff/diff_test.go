@@ -0,0 +1,153 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		expected string
+	}{
+		{
+			name:     "equal",
+			old:      "a\nb\n",
+			new:      "a\nb\n",
+			expected: "",
+		},
+		{
+			name: "separate hunks",
+			old:  "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n",
+			new:  "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nK\nl\n",
+			expected: `--- old
++++ new
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -8,4 +8,5 @@
+ h
+ i
+ j
+-k
++K
++l
+`,
+		},
+		{
+			name: "new file",
+			old:  "",
+			new:  "x\ny\n",
+			expected: `--- old
++++ new
+@@ -0,0 +1,2 @@
++x
++y
+`,
+		},
+		{
+			name: "missing newline",
+			old:  "a\nb",
+			new:  "a\nb\n",
+			expected: `--- old
++++ new
+@@ -1,2 +1,2 @@
+ a
+-b
+\ No newline at end of file
++b
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := Unified("old", "new", tt.old, tt.new); result != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+// lcsLength is the length of the longest common subsequence of a and b.
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func TestLineOpsShortest(t *testing.T) {
+	for seed := int64(0); seed < 500; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		lines := func() []string {
+			result := make([]string, rng.Intn(30))
+			for i := range result {
+				result[i] = string(rune('a'+rng.Intn(4))) + "\n"
+			}
+			return result
+		}
+		a, b := lines(), lines()
+
+		var old, new []string
+		kept := 0
+		for _, o := range lineOps(a, b) {
+			if o.kind != '+' {
+				old = append(old, o.line)
+			}
+			if o.kind != '-' {
+				new = append(new, o.line)
+			}
+			if o.kind == ' ' {
+				kept++
+			}
+		}
+
+		if strings.Join(old, "") != strings.Join(a, "") || strings.Join(new, "") != strings.Join(b, "") {
+			t.Fatalf("Seed %d: edit script does not turn %q into %q", seed, a, b)
+		}
+		if expected := lcsLength(a, b); kept != expected {
+			t.Fatalf("Seed %d: expected %d kept lines, got %d", seed, expected, kept)
+		}
+	}
+}
+
+func TestUnifiedLargeFile(t *testing.T) {
+	var old strings.Builder
+	for i := 0; i < 50000; i++ {
+		fmt.Fprintf(&old, "- [ ] Task %d\n", i)
+	}
+	new := strings.Replace(old.String(), "- [ ] Task 10\n", "- [x] Task 10\n", 1)
+	new = strings.Replace(new, "- [ ] Task 49990\n", "", 1)
+
+	result := Unified("old", "new", old.String(), new)
+	if !strings.Contains(result, "@@ -8,7 +8,7 @@") || !strings.Contains(result, "-- [ ] Task 49990\n") {
+		t.Errorf("Unexpected diff of large file:\n%s", result)
+	}
+}
+
+func TestColorize(t *testing.T) {
+	diff := "--- old\n+++ new\n@@ -1 +1 @@\n-a\n+b\n c\n"
+	expected := "\x1b[1m--- old\x1b[0m\n\x1b[1m+++ new\x1b[0m\n\x1b[36m@@ -1 +1 @@\x1b[0m\n\x1b[31m-a\x1b[0m\n\x1b[32m+b\x1b[0m\n c\n"
+
+	if result := Colorize(diff); result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
@@ -0,0 +1,143 @@
+package exchange
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+	"github.com/ahmaruff/tada/internal/processor"
+)
+
+// TimesheetOptions selects and fills timesheet rows.
+type TimesheetOptions struct {
+	From, To    *time.Time // only days within From..To
+	Projects    []string   // only these projects, ignoring case
+	HoursPerDay float64    // split evenly across a day's rows; 0 leaves hours empty
+}
+
+// TimesheetRow is one task on one day it was active.
+type TimesheetRow struct {
+	Date    time.Time
+	Project string
+	ID      string
+	Title   string
+	Status  model.TaskStatus
+	Hours   float64
+}
+
+// TimesheetRows returns a row per task per day it was active: every day of
+// a Backlog or Archives task's StartDate..EndDate, and the date of every
+// Todo/Done entry. A task appears once per day, with the status of that
+// day's Todo/Done entry if there is one. Rows are sorted by date.
+func TimesheetRows(sections []model.Section, options TimesheetOptions) []TimesheetRow {
+	// Todo/Done entries often leave out what Backlog already says
+	backlog := make(map[string]model.Task)
+	for _, section := range sections {
+		if section.Name == model.SectionBacklog {
+			for _, task := range section.Tasks {
+				if task.ID != "" {
+					backlog[task.ID] = task
+				}
+			}
+		}
+	}
+
+	var rows []TimesheetRow
+	index := make(map[string]int)
+	add := func(task model.Task, day time.Time, dayEntry bool) {
+		if options.From != nil && day.Before(*options.From) {
+			return
+		}
+		if options.To != nil && day.After(*options.To) {
+			return
+		}
+
+		key := day.Format("2006-01-02") + "|" + processor.TaskKey(task)
+		if i, ok := index[key]; ok {
+			if dayEntry {
+				rows[i].Status = task.Status
+			}
+			return
+		}
+
+		index[key] = len(rows)
+		rows = append(rows, TimesheetRow{Date: day, Project: task.Project, ID: task.ID, Title: task.Title, Status: task.Status})
+	}
+
+	for _, section := range sections {
+		for _, task := range section.Tasks {
+			if known, ok := backlog[task.ID]; ok {
+				if task.Project == "" {
+					task.Project = known.Project
+				}
+				if task.Title == "" {
+					task.Title = known.Title
+				}
+			}
+
+			if len(options.Projects) > 0 && !processor.ContainsFold(options.Projects, task.Project) {
+				continue
+			}
+			if task.StartDate == nil {
+				continue
+			}
+
+			switch {
+			case section.Name.HasDateGroups():
+				add(task, *task.StartDate, true)
+			case section.Name == model.SectionBacklog || section.Name == model.SectionArchives:
+				end := *task.StartDate
+				if task.EndDate != nil && task.EndDate.After(end) {
+					end = *task.EndDate
+				}
+				for day := *task.StartDate; !day.After(end); day = day.AddDate(0, 0, 1) {
+					add(task, day, false)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].Date.Before(rows[j].Date)
+	})
+
+	if options.HoursPerDay > 0 {
+		perDay := make(map[string]int)
+		for _, row := range rows {
+			perDay[row.Date.Format("2006-01-02")]++
+		}
+		for i := range rows {
+			rows[i].Hours = options.HoursPerDay / float64(perDay[rows[i].Date.Format("2006-01-02")])
+		}
+	}
+
+	return rows
+}
+
+// ExportCSV writes timesheet rows with a header line. Hours are rounded to
+// two decimals, and left empty for rows without hours.
+func ExportCSV(w io.Writer, rows []TimesheetRow) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"date", "project", "id", "title", "status", "hours"}); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		hours := ""
+		if row.Hours > 0 {
+			hours = strconv.FormatFloat(math.Round(row.Hours*100)/100, 'f', -1, 64)
+		}
+
+		record := []string{row.Date.Format("2006-01-02"), row.Project, row.ID, row.Title, row.Status.Name(), hours}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,87 @@
+package exchange
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/parser"
+)
+
+const timesheetInput = `## Backlog
+- [x] Parser <!-- @tada|#1|2025-09-12 - 2025-09-14 -->
+- [ ] Login, SSO <!-- @crm|#2 -->
+
+## Todo
+### 2025-09-13
+- [-] Parser <!-- #1 -->
+- [-] Login, SSO <!-- #2 -->
+
+## Done
+### 2025-09-14
+- [x] Parser <!-- #1 -->
+- [x] Call client <!-- @crm -->
+
+## Archives
+- [x] Release <!-- @tada|#3|2025-09-11 -->
+`
+
+func TestTimesheetRows(t *testing.T) {
+	sections, err := parser.ParseContent(bufio.NewScanner(strings.NewReader(timesheetInput)))
+	if err != nil {
+		t.Fatalf("ParseContent failed: %v", err)
+	}
+
+	date := func(day int) *time.Time {
+		d := time.Date(2025, 9, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	tests := []struct {
+		name     string
+		options  TimesheetOptions
+		expected string
+	}{
+		{
+			name: "all days",
+			expected: `date,project,id,title,status,hours
+2025-09-11,tada,3,Release,done,
+2025-09-12,tada,1,Parser,done,
+2025-09-13,tada,1,Parser,in-progress,
+2025-09-13,crm,2,"Login, SSO",in-progress,
+2025-09-14,tada,1,Parser,done,
+2025-09-14,crm,,Call client,done,
+`,
+		},
+		{
+			name:    "range, project and hours",
+			options: TimesheetOptions{From: date(13), To: date(14), Projects: []string{"CRM"}, HoursPerDay: 8},
+			expected: `date,project,id,title,status,hours
+2025-09-13,crm,2,"Login, SSO",in-progress,8
+2025-09-14,crm,,Call client,done,8
+`,
+		},
+		{
+			name:    "hours split across a day",
+			options: TimesheetOptions{From: date(13), To: date(13), HoursPerDay: 7},
+			expected: `date,project,id,title,status,hours
+2025-09-13,tada,1,Parser,in-progress,3.5
+2025-09-13,crm,2,"Login, SSO",in-progress,3.5
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := ExportCSV(&out, TimesheetRows(sections, tt.options)); err != nil {
+				t.Fatalf("ExportCSV failed: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package exchange
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+)
+
+// ICSOptions controls the calendar written by ExportICS.
+type ICSOptions struct {
+	Events bool      // also write an all-day VEVENT per task
+	Now    time.Time // DTSTAMP of every entry
+}
+
+// ExportICS writes the tasks that have a start date as an iCalendar file
+// with one VTODO each. UIDs are derived from task IDs, or from the project,
+// title and start date of tasks without one, so they stay the same between
+// exports and calendar apps update entries instead of duplicating them.
+func ExportICS(w io.Writer, tasks []model.Task, options ICSOptions) error {
+	ics := &icsWriter{w: w}
+	stamp := options.Now.UTC().Format("20060102T150405Z")
+
+	ics.line("BEGIN:VCALENDAR")
+	ics.line("VERSION:2.0")
+	ics.line("PRODID:-//tada//tada export//EN")
+	ics.line("CALSCALE:GREGORIAN")
+	ics.line("X-WR-CALNAME:tada")
+
+	for _, task := range tasks {
+		if task.StartDate == nil {
+			continue
+		}
+
+		end := *task.StartDate
+		if task.EndDate != nil && task.EndDate.After(end) {
+			end = *task.EndDate
+		}
+		uid := icsUID(task)
+
+		ics.line("BEGIN:VTODO")
+		ics.line("UID:" + uid + "@tada")
+		ics.line("DTSTAMP:" + stamp)
+		ics.line("SUMMARY:" + icsText(task.Title))
+		if task.Project != "" {
+			ics.line("CATEGORIES:" + icsText(task.Project))
+		}
+		if description := icsDescription(task); description != "" {
+			ics.line("DESCRIPTION:" + icsText(description))
+		}
+		// DUE must be later than DTSTART, so single-day tasks only have DUE
+		if end.After(*task.StartDate) {
+			ics.line("DTSTART;VALUE=DATE:" + task.StartDate.Format("20060102"))
+		}
+		ics.line("DUE;VALUE=DATE:" + end.Format("20060102"))
+		ics.line("STATUS:" + icsStatus(task.Status))
+		if task.Status == model.StatusDone {
+			ics.line("COMPLETED:" + end.Format("20060102T150405Z"))
+			ics.line("PERCENT-COMPLETE:100")
+		}
+		ics.line("END:VTODO")
+
+		if options.Events {
+			ics.line("BEGIN:VEVENT")
+			ics.line("UID:" + uid + "-event@tada")
+			ics.line("DTSTAMP:" + stamp)
+			ics.line("SUMMARY:" + icsText(task.Title))
+			if task.Project != "" {
+				ics.line("CATEGORIES:" + icsText(task.Project))
+			}
+			// All-day events end on the day after their last day
+			ics.line("DTSTART;VALUE=DATE:" + task.StartDate.Format("20060102"))
+			ics.line("DTEND;VALUE=DATE:" + end.AddDate(0, 0, 1).Format("20060102"))
+			ics.line("TRANSP:TRANSPARENT")
+			ics.line("END:VEVENT")
+		}
+	}
+
+	ics.line("END:VCALENDAR")
+	return ics.err
+}
+
+// icsWriter writes CRLF-terminated content lines folded at 75 octets, as
+// RFC 5545 requires, and keeps the first write error.
+type icsWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ics *icsWriter) line(content string) {
+	if ics.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range content {
+		size := len(string(r))
+		if width+size > 75 {
+			// Continuation lines start with a space, which counts too
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+
+	_, ics.err = io.WriteString(ics.w, b.String())
+}
+
+func icsUID(task model.Task) string {
+	if task.ID != "" {
+		return "task-" + task.ID
+	}
+
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s\x00%s\x00%s", task.Project, task.Title, task.StartDate.Format("2006-01-02"))))
+	return "hash-" + hex.EncodeToString(sum[:])[:12]
+}
+
+func icsStatus(status model.TaskStatus) string {
+	switch status {
+	case model.StatusDone:
+		return "COMPLETED"
+	case model.StatusInProgress:
+		return "IN-PROCESS"
+	default:
+		return "NEEDS-ACTION"
+	}
+}
+
+func icsDescription(task model.Task) string {
+	lines := append([]string{}, task.Description...)
+	model.WalkSubtasks(task.SubTasks, func(subtask *model.Subtask, depth int) {
+		lines = append(lines, fmt.Sprintf("%s- %s %s", strings.Repeat("  ", depth), subtask.Status, subtask.Content))
+	})
+	return strings.Join(lines, "\n")
+}
+
+// icsText escapes a TEXT value.
+func icsText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,90 @@
+package exchange
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+)
+
+func TestExportICS(t *testing.T) {
+	date := func(day int) *time.Time {
+		d := time.Date(2025, 9, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	tasks := []model.Task{
+		{ID: "1", Title: "Parser, lexer; tests", Project: "tada", Status: model.StatusDone,
+			StartDate: date(13), EndDate: date(14), Description: []string{"first line"},
+			SubTasks: []model.Subtask{{Status: model.StatusTodo, Content: "docs"}}},
+		{Title: "Call client", Status: model.StatusInProgress, StartDate: date(15)},
+		{ID: "3", Title: "Undated", Status: model.StatusTodo},
+	}
+	now := time.Date(2025, 9, 20, 10, 30, 0, 0, time.UTC)
+
+	var out bytes.Buffer
+	if err := ExportICS(&out, tasks, ICSOptions{Events: true, Now: now}); err != nil {
+		t.Fatalf("ExportICS failed: %v", err)
+	}
+	ics := out.String()
+
+	for _, expected := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"UID:task-1@tada\r\n",
+		"DTSTAMP:20250920T103000Z\r\n",
+		"SUMMARY:Parser\\, lexer\\; tests\r\n",
+		"CATEGORIES:tada\r\n",
+		"DESCRIPTION:first line\\n- [ ] docs\r\n",
+		"DTSTART;VALUE=DATE:20250913\r\nDUE;VALUE=DATE:20250914\r\nSTATUS:COMPLETED\r\nCOMPLETED:20250914T000000Z\r\n",
+		"UID:task-1-event@tada\r\n",
+		"DTEND;VALUE=DATE:20250915\r\n",
+		"DUE;VALUE=DATE:20250915\r\nSTATUS:IN-PROCESS\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, expected) {
+			t.Errorf("Expected calendar to contain %q, got:\n%s", expected, ics)
+		}
+	}
+
+	if strings.Contains(ics, "Undated") {
+		t.Errorf("Expected tasks without dates to be skipped")
+	}
+	if strings.Count(ics, "BEGIN:VTODO") != 2 || strings.Count(ics, "BEGIN:VEVENT") != 2 {
+		t.Errorf("Expected 2 todos and 2 events, got:\n%s", ics)
+	}
+
+	// UIDs of tasks without ID are stable
+	var again bytes.Buffer
+	if err := ExportICS(&again, tasks, ICSOptions{Now: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("ExportICS failed: %v", err)
+	}
+	uid := func(s string) string {
+		start := strings.Index(s, "UID:hash-")
+		return s[start : start+strings.Index(s[start:], "\r\n")]
+	}
+	if uid(ics) != uid(again.String()) {
+		t.Errorf("Expected stable UID, got %s and %s", uid(ics), uid(again.String()))
+	}
+}
+
+func TestICSLineFolding(t *testing.T) {
+	var out bytes.Buffer
+	ics := &icsWriter{w: &out}
+	ics.line("SUMMARY:" + strings.Repeat("é", 40))
+
+	for i, line := range strings.Split(strings.TrimSuffix(out.String(), "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Line %d is %d octets long: %q", i, len(line), line)
+		}
+		if i > 0 && !strings.HasPrefix(line, " ") {
+			t.Errorf("Expected continuation line %d to start with a space", i)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(out.String(), "\r\n ", "")
+	if unfolded != "SUMMARY:"+strings.Repeat("é", 40)+"\r\n" {
+		t.Errorf("Expected folding to keep characters intact, got %q", unfolded)
+	}
+}
@@ -0,0 +1,230 @@
+// Package exchange converts tasks to and from formats used by other tools.
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+)
+
+// SchemaVersion is the version of the JSON document written by ExportJSON.
+// It is increased whenever a field changes meaning or is removed; adding a
+// field does not change it.
+const SchemaVersion = 1
+
+// Document is the JSON form of a tada file:
+//
+//	{
+//	  "version": 1,
+//	  "sections": [{
+//	    "name": "Todo",
+//	    "groups": [{"date": "2025-09-14", "label": "Minggu"}],
+//	    "tasks": [{
+//	      "id": "3", "title": "Parser", "project": "tada",
+//	      "group": "2025-09-14",            // date header the task is under
+//	      "status": "in-progress",          // todo, in-progress or done
+//	      "start_date": "2025-09-14",       // YYYY-MM-DD, omitted if unset
+//	      "end_date": "2025-09-14",
+//	      "description": ["line"],
+//	      "subtasks": [{"status": "done", "content": "lexer"}]
+//	    }]
+//	  }]
+//	}
+//
+// Section names are the standard Backlog, Todo, Done and Archives (not the
+// configured header titles) or the header text of any other section. Free
+// text between tasks is not exported.
+type Document struct {
+	Version  int           `json:"version"`
+	Sections []JSONSection `json:"sections"`
+}
+
+type JSONSection struct {
+	Name   string      `json:"name"`
+	Groups []JSONGroup `json:"groups,omitempty"`
+	Tasks  []JSONTask  `json:"tasks"`
+}
+
+// JSONGroup is a "### YYYY-MM-DD label" date header.
+type JSONGroup struct {
+	Date  string `json:"date"`
+	Label string `json:"label,omitempty"`
+}
+
+type JSONTask struct {
+	ID          string        `json:"id,omitempty"`
+	Title       string        `json:"title"`
+	Project     string        `json:"project,omitempty"`
+	Status      string        `json:"status"`
+	Group       string        `json:"group,omitempty"`
+	StartDate   string        `json:"start_date,omitempty"`
+	EndDate     string        `json:"end_date,omitempty"`
+	Description []string      `json:"description,omitempty"`
+	Subtasks    []JSONSubtask `json:"subtasks,omitempty"`
+	Reopened    bool          `json:"reopened,omitempty"`
+}
+
+type JSONSubtask struct {
+	Status   string        `json:"status"`
+	Content  string        `json:"content"`
+	Subtasks []JSONSubtask `json:"subtasks,omitempty"`
+}
+
+// ExportJSON writes sections as an indented JSON Document.
+func ExportJSON(w io.Writer, sections []model.Section) error {
+	doc := Document{Version: SchemaVersion, Sections: []JSONSection{}}
+
+	for _, section := range sections {
+		out := JSONSection{Name: string(section.Name), Tasks: []JSONTask{}}
+		for _, group := range section.Groups {
+			out.Groups = append(out.Groups, JSONGroup{Date: group.Date, Label: group.Label})
+		}
+
+		for _, task := range section.Tasks {
+			jsonTask := JSONTask{
+				ID:          task.ID,
+				Title:       task.Title,
+				Project:     task.Project,
+				Status:      task.Status.Name(),
+				Group:       task.Position.DateHeader,
+				StartDate:   model.FormatDate(task.StartDate),
+				EndDate:     model.FormatDate(task.EndDate),
+				Description: task.Description,
+				Reopened:    task.Reopened,
+			}
+			jsonTask.Subtasks = exportSubtasks(task.SubTasks)
+			out.Tasks = append(out.Tasks, jsonTask)
+		}
+
+		doc.Sections = append(doc.Sections, out)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// ImportJSON reads a JSON Document written by ExportJSON.
+func ImportJSON(r io.Reader) ([]model.Section, error) {
+	var doc Document
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if doc.Version < 1 || doc.Version > SchemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %d, expected 1 to %d", doc.Version, SchemaVersion)
+	}
+
+	sections := make([]model.Section, 0, len(doc.Sections))
+	for _, in := range doc.Sections {
+		if in.Name == "" {
+			return nil, fmt.Errorf("section without a name")
+		}
+
+		section := model.Section{Name: model.SectionName(in.Name), Tasks: []model.Task{}}
+		for _, group := range in.Groups {
+			if _, err := time.Parse("2006-01-02", group.Date); err != nil {
+				return nil, fmt.Errorf("section %s: invalid group date %q", in.Name, group.Date)
+			}
+			section.Groups = append(section.Groups, model.DateGroup{Date: group.Date, Label: group.Label})
+		}
+
+		for i, in := range in.Tasks {
+			task, err := importTask(in)
+			if err != nil {
+				return nil, fmt.Errorf("section %s, task %d: %w", section.Name, i+1, err)
+			}
+			task.Position = model.Position{Section: section.Name, DateHeader: in.Group}
+			section.Tasks = append(section.Tasks, task)
+		}
+
+		sections = append(sections, section)
+	}
+
+	return sections, nil
+}
+
+func importTask(in JSONTask) (model.Task, error) {
+	status, err := model.ParseStatusName(in.Status)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	task := model.Task{
+		ID:          in.ID,
+		Title:       in.Title,
+		Project:     in.Project,
+		Status:      status,
+		Description: []string{},
+		SubTasks:    []model.Subtask{},
+		Reopened:    in.Reopened,
+	}
+	if in.Title == "" {
+		return task, fmt.Errorf("missing title")
+	}
+
+	if _, err := parseDate(in.Group); err != nil {
+		return task, fmt.Errorf("group: %w", err)
+	}
+	if task.StartDate, err = parseDate(in.StartDate); err != nil {
+		return task, fmt.Errorf("start_date: %w", err)
+	}
+	if task.EndDate, err = parseDate(in.EndDate); err != nil {
+		return task, fmt.Errorf("end_date: %w", err)
+	}
+
+	task.Description = append(task.Description, in.Description...)
+	subtasks, err := importSubtasks(in.Subtasks)
+	if err != nil {
+		return task, err
+	}
+	task.SubTasks = append(task.SubTasks, subtasks...)
+
+	return task, nil
+}
+
+func exportSubtasks(subtasks []model.Subtask) []JSONSubtask {
+	var out []JSONSubtask
+	for _, subtask := range subtasks {
+		out = append(out, JSONSubtask{
+			Status:   subtask.Status.Name(),
+			Content:  subtask.Content,
+			Subtasks: exportSubtasks(subtask.Children),
+		})
+	}
+	return out
+}
+
+func importSubtasks(in []JSONSubtask) ([]model.Subtask, error) {
+	var subtasks []model.Subtask
+	for _, subtask := range in {
+		status, err := model.ParseStatusName(subtask.Status)
+		if err != nil {
+			return nil, fmt.Errorf("subtask %q: %w", subtask.Content, err)
+		}
+
+		children, err := importSubtasks(subtask.Subtasks)
+		if err != nil {
+			return nil, err
+		}
+		subtasks = append(subtasks, model.Subtask{Status: status, Content: subtask.Content, Children: children})
+	}
+	return subtasks, nil
+}
+
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("expected YYYY-MM-DD, got %q", value)
+	}
+	return &date, nil
+}
@@ -0,0 +1,130 @@
+package exchange
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ahmaruff/tada/internal/parser"
+	"github.com/ahmaruff/tada/internal/writer"
+)
+
+const jsonInput = `## Backlog
+- [-] Parser <!-- @tada|#1|2025-09-13 - 2025-09-14 -->
+  Write parser function
+  - [x] lexer
+  - [-] errors
+    - [x] positions
+      - [ ] columns
+- [ ] No ID
+- [-] Lexer <!-- #2|2025-09-12 - -->
+
+## Todo
+### 2025-09-13 - Saturday
+
+### 2025-09-14 - Sunday
+- [-] Parser <!-- @tada|#1 -->
+
+### 2025-09-15 - Senin
+
+## Done
+### 2025-09-14 - Minggu
+- [x] Parser <!-- @tada|#1|2025-09-13 - 2025-09-14 -->
+
+### 2025-09-12
+- [x] Old parser <!-- #3|2025-09-12 - 2025-09-14 -->
+
+## Notes
+- [ ] Custom section task
+`
+
+func TestJSONRoundTrip(t *testing.T) {
+	sections, err := parser.ParseContent(bufio.NewScanner(strings.NewReader(jsonInput)))
+	if err != nil {
+		t.Fatalf("ParseContent failed: %v", err)
+	}
+
+	var exported bytes.Buffer
+	if err := ExportJSON(&exported, sections); err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+
+	for _, expected := range []string{
+		`"version": 1`,
+		`"name": "Notes"`,
+		`"label": "Sunday"`,
+		`"label": "Senin"`,
+		`"start_date": "2025-09-12"
+        }`,
+		`"status": "done",
+          "group": "2025-09-12",
+          "start_date": "2025-09-12",
+          "end_date": "2025-09-14"`,
+		`"group": "2025-09-14"`,
+		`"status": "in-progress"`,
+		`"start_date": "2025-09-13"`,
+		`"end_date": "2025-09-14"`,
+		`"content": "lexer"`,
+		`"subtasks": [
+                    {
+                      "status": "todo",
+                      "content": "columns"
+                    }
+                  ]`,
+	} {
+		if !strings.Contains(exported.String(), expected) {
+			t.Errorf("Expected export to contain %s, got:\n%s", expected, exported.String())
+		}
+	}
+
+	imported, err := ImportJSON(bytes.NewReader(exported.Bytes()))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+
+	markdown := writer.GenerateInputMarkdown(imported)
+	if markdown != jsonInput {
+		t.Errorf("Expected markdown to survive JSON round trip.\nExpected:\n%s\ngot:\n%s", jsonInput, markdown)
+	}
+
+	reparsed, err := parser.ParseContent(bufio.NewScanner(strings.NewReader(markdown)))
+	if err != nil {
+		t.Fatalf("ParseContent failed: %v", err)
+	}
+
+	var again bytes.Buffer
+	if err := ExportJSON(&again, reparsed); err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+
+	if again.String() != exported.String() {
+		t.Errorf("Expected JSON to survive markdown round trip.\nFirst:\n%s\nSecond:\n%s\nMarkdown:\n%s",
+			exported.String(), again.String(), markdown)
+	}
+}
+
+func TestImportJSONErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"not json", `## Backlog`},
+		{"missing version", `{"sections": []}`},
+		{"future version", `{"version": 99, "sections": []}`},
+		{"unknown field", `{"version": 1, "sections": [], "extra": true}`},
+		{"unnamed section", `{"version": 1, "sections": [{"tasks": []}]}`},
+		{"bad status", `{"version": 1, "sections": [{"name": "Backlog", "tasks": [{"title": "a", "status": "blocked"}]}]}`},
+		{"bad date", `{"version": 1, "sections": [{"name": "Backlog", "tasks": [{"title": "a", "status": "todo", "start_date": "13/09/2025"}]}]}`},
+		{"bad group", `{"version": 1, "sections": [{"name": "Todo", "tasks": [{"title": "a", "status": "todo", "group": "Monday"}]}]}`},
+		{"missing title", `{"version": 1, "sections": [{"name": "Backlog", "tasks": [{"status": "todo"}]}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ImportJSON(strings.NewReader(tt.content)); err == nil {
+				t.Errorf("Expected error for %s", tt.content)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package exchange
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/ahmaruff/tada/internal/model"
+	"github.com/ahmaruff/tada/internal/parser"
+	"github.com/ahmaruff/tada/internal/processor"
+)
+
+var obsidianTaskRegex = regexp.MustCompile(`^(\s*)[-*+]\s\[(.)\]\s+(.+)$`)
+
+// ImportObsidian converts the tasks of an Obsidian note written for the
+// Obsidian Tasks plugin, wherever they are in the note:
+//
+//   - [x] Call client #crm 🛫 2025-01-15 📅 2025-01-20 ✅ 2025-01-18 ⏫
+//
+// Top-level tasks go to Backlog, indented tasks become subtasks, nested as
+// in the note, and other indented lines descriptions. Statuses [/] (in
+// progress), [x]/[X] (done) and [-] (cancelled, imported as done with a
+// "Cancelled" description) are mapped; anything else is todo. Markers are
+// read as by parser.ParseObsidianMarkers and the first #tag becomes the
+// project; subtasks keep their markers in their text. Tasks without a 🆔
+// get an ID in the given style, and completed tasks also get an entry in
+// Done under their completion date.
+func ImportObsidian(r io.Reader, style processor.IDStyle) ([]model.Section, error) {
+	backlog := model.Section{Name: model.SectionBacklog, Tasks: []model.Task{}}
+
+	var current *model.Task
+	var subtasks model.SubtaskBuilder
+	indent := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if matches := obsidianTaskRegex.FindStringSubmatch(line); matches != nil {
+			lineIndent := len(strings.ReplaceAll(matches[1], "\t", "    "))
+			status, cancelled := obsidianStatus(matches[2])
+
+			if current != nil && lineIndent > indent {
+				// Subtasks have no fields for markers, so they keep them
+				content := strings.TrimSpace(matches[3])
+				if cancelled {
+					content += " (cancelled)"
+				}
+				subtasks.Add(&current.SubTasks, lineIndent, model.Subtask{Status: status, Content: content})
+				continue
+			}
+
+			backlog.Tasks = append(backlog.Tasks, obsidianTask(matches[3], status, cancelled))
+			current = &backlog.Tasks[len(backlog.Tasks)-1]
+			subtasks = model.SubtaskBuilder{}
+			indent = lineIndent
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		lineIndent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if current != nil && trimmed != "" && lineIndent > indent {
+			current.Description = append(current.Description, trimmed)
+			continue
+		}
+
+		// Anything else ends the current task
+		current = nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return withDoneEntries(backlog, style), nil
+}
+
+func obsidianTask(text string, status model.TaskStatus, cancelled bool) model.Task {
+	title, markers := parser.ParseObsidianMarkers(text)
+	task := model.Task{Status: status, Description: []string{}, SubTasks: []model.Subtask{}}
+
+	var words []string
+	for _, word := range strings.Fields(title) {
+		if strings.HasPrefix(word, "#") && len(word) > 1 && task.Project == "" {
+			task.Project = word[1:]
+			continue
+		}
+		words = append(words, word)
+	}
+	task.Title = strings.Join(words, " ")
+
+	markers.Apply(&task)
+	if cancelled {
+		task.Description = append(task.Description, "Cancelled")
+	}
+	return task
+}
+
+func obsidianStatus(marker string) (model.TaskStatus, bool) {
+	switch marker {
+	case "x", "X":
+		return model.StatusDone, false
+	case "/":
+		return model.StatusInProgress, false
+	case "-":
+		return model.StatusDone, true
+	default:
+		return model.StatusTodo, false
+	}
+}
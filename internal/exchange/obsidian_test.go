@@ -0,0 +1,59 @@
+package exchange
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ahmaruff/tada/internal/processor"
+	"github.com/ahmaruff/tada/internal/writer"
+)
+
+func TestImportObsidian(t *testing.T) {
+	input := `# Project notes
+
+Some prose that is not a task.
+
+- [x] Write parser #tada 🛫 2025-09-12 ✅ 2025-09-14 ⏫
+    Handles nested lists
+    - [x] lexer
+    - [ ] docs 📅 2025-09-20
+        - [x] README ✅ 2025-09-13
+        - [-] Wiki
+- [/] Call client #crm ➕ 2025-09-10 📅 2025-09-20 🆔 call
+- [-] Old idea ❌ 2025-09-13
+* [ ] Plain task
+
+- not a task
+`
+
+	sections, err := ImportObsidian(strings.NewReader(input), processor.IDStyleSequential)
+	if err != nil {
+		t.Fatalf("ImportObsidian failed: %v", err)
+	}
+
+	expected := `## Backlog
+- [x] Write parser <!-- @tada|#1|2025-09-12 - 2025-09-14 -->
+  Priority: high
+  Handles nested lists
+  - [x] lexer
+  - [ ] docs 📅 2025-09-20
+    - [x] README ✅ 2025-09-13
+    - [x] Wiki (cancelled)
+- [-] Call client <!-- @crm|#call|2025-09-10 - -->
+  Due: 2025-09-20
+- [x] Old idea <!-- #2|2025-09-13 -->
+  Cancelled
+- [ ] Plain task <!-- #3 -->
+
+## Done
+### 2025-09-14 - Minggu
+- [x] Write parser <!-- @tada|#1 -->
+
+### 2025-09-13 - Sabtu
+- [x] Old idea <!-- #2 -->
+`
+
+	if result := writer.GenerateInputMarkdown(sections); result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
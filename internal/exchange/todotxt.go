@@ -0,0 +1,143 @@
+package exchange
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+	"github.com/ahmaruff/tada/internal/processor"
+)
+
+var todoTxtPriority = regexp.MustCompile(`^\(([A-Z])\)$`)
+
+// ImportTodoTxt reads a todo.txt file (one task per line, see
+// https://github.com/todotxt/todo.txt) into a Backlog and a Done section:
+//
+//	x (A) 2025-09-14 2025-09-10 Call client +crm @phone
+//
+// "x" marks the task done, the first date after it is the completion date
+// and the next one the creation date; open tasks only have a creation date.
+// The first +project becomes the project and the priority a "Priority: A"
+// description line; contexts and key:value tags stay in the title. Every
+// task gets an ID in the given style, and completed tasks also get an entry
+// in Done under their completion date.
+func ImportTodoTxt(r io.Reader, style processor.IDStyle) ([]model.Section, error) {
+	backlog := model.Section{Name: model.SectionBacklog, Tasks: []model.Task{}}
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		task, err := parseTodoTxtLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		backlog.Tasks = append(backlog.Tasks, task)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return withDoneEntries(backlog, style), nil
+}
+
+// withDoneEntries assigns IDs to the Backlog tasks and adds a Done section
+// with an entry for every completed task under its end date, newest first
+// like tada adds them.
+func withDoneEntries(backlog model.Section, style processor.IDStyle) []model.Section {
+	sections, _ := processor.AssignIDs([]model.Section{backlog}, style)
+
+	done := model.Section{Name: model.SectionDone, Tasks: []model.Task{}}
+	for _, task := range sections[0].Tasks {
+		if task.Status != model.StatusDone || task.EndDate == nil {
+			continue
+		}
+		done.Tasks = append(done.Tasks, model.Task{
+			ID:          task.ID,
+			Title:       task.Title,
+			Project:     task.Project,
+			Status:      model.StatusDone,
+			StartDate:   task.EndDate,
+			EndDate:     task.EndDate,
+			Description: []string{},
+			SubTasks:    []model.Subtask{},
+		})
+	}
+
+	sort.SliceStable(done.Tasks, func(i, j int) bool {
+		return done.Tasks[i].StartDate.After(*done.Tasks[j].StartDate)
+	})
+
+	if len(done.Tasks) > 0 {
+		sections = append(sections, done)
+	}
+	return sections
+}
+
+func parseTodoTxtLine(line string) (model.Task, error) {
+	task := model.Task{Status: model.StatusTodo, Description: []string{}, SubTasks: []model.Subtask{}}
+	fields := strings.Fields(line)
+
+	if len(fields) > 0 && fields[0] == "x" {
+		task.Status = model.StatusDone
+		fields = fields[1:]
+	}
+
+	if len(fields) > 0 {
+		if matches := todoTxtPriority.FindStringSubmatch(fields[0]); matches != nil {
+			task.Description = append(task.Description, "Priority: "+matches[1])
+			fields = fields[1:]
+		}
+	}
+
+	var dates []time.Time
+	for len(fields) > 0 && len(dates) < 2 {
+		date, err := time.Parse("2006-01-02", fields[0])
+		if err != nil {
+			break
+		}
+		dates = append(dates, date)
+		fields = fields[1:]
+	}
+
+	switch {
+	case task.Status == model.StatusDone && len(dates) == 2:
+		task.StartDate, task.EndDate = &dates[1], &dates[0]
+	case task.Status == model.StatusDone && len(dates) == 1:
+		task.StartDate, task.EndDate = &dates[0], &dates[0]
+	case len(dates) == 2:
+		return task, fmt.Errorf("open task with two dates: %s", line)
+	case len(dates) == 1:
+		task.StartDate = &dates[0]
+	}
+
+	var title []string
+	for _, field := range fields {
+		if strings.HasPrefix(field, "+") && len(field) > 1 && task.Project == "" {
+			task.Project = field[1:]
+			continue
+		}
+		if strings.HasPrefix(field, "pri:") && len(field) == 5 && task.Status == model.StatusDone {
+			// Completed tasks often keep their priority as a pri: tag
+			task.Description = append(task.Description, "Priority: "+strings.ToUpper(field[4:]))
+			continue
+		}
+		title = append(title, field)
+	}
+
+	task.Title = strings.Join(title, " ")
+	if task.Title == "" {
+		return task, fmt.Errorf("task without a description: %s", line)
+	}
+	return task, nil
+}
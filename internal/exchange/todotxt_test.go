@@ -0,0 +1,68 @@
+package exchange
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ahmaruff/tada/internal/model"
+	"github.com/ahmaruff/tada/internal/processor"
+	"github.com/ahmaruff/tada/internal/writer"
+)
+
+func TestImportTodoTxt(t *testing.T) {
+	input := `(A) 2025-09-10 Call client +crm @phone due:2025-09-20
+
+x 2025-09-14 2025-09-12 Write parser +tada pri:B
+x 2025-09-13 Review PR
+x Undated done
+Plain task +tada +docs
+`
+
+	sections, err := ImportTodoTxt(strings.NewReader(input), processor.IDStyleSequential)
+	if err != nil {
+		t.Fatalf("ImportTodoTxt failed: %v", err)
+	}
+
+	expected := `## Backlog
+- [ ] Call client @phone due:2025-09-20 <!-- @crm|#1|2025-09-10 - -->
+  Priority: A
+- [x] Write parser <!-- @tada|#2|2025-09-12 - 2025-09-14 -->
+  Priority: B
+- [x] Review PR <!-- #3|2025-09-13 -->
+- [x] Undated done <!-- #4 -->
+- [ ] Plain task +docs <!-- @tada|#5 -->
+
+## Done
+### 2025-09-14 - Minggu
+- [x] Write parser <!-- @tada|#2 -->
+
+### 2025-09-13 - Sabtu
+- [x] Review PR <!-- #3 -->
+`
+
+	if result := writer.GenerateInputMarkdown(sections); result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+
+	if sections[0].Tasks[0].Status != model.StatusTodo || sections[0].Tasks[1].Status != model.StatusDone {
+		t.Errorf("Unexpected statuses %v, %v", sections[0].Tasks[0].Status, sections[0].Tasks[1].Status)
+	}
+}
+
+func TestImportTodoTxtErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"open task with two dates", "2025-09-10 2025-09-11 Call client\n"},
+		{"no description", "x 2025-09-14 +crm\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ImportTodoTxt(strings.NewReader(tt.input), processor.IDStyleSequential); err == nil {
+				t.Errorf("Expected error for %q", tt.input)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+// Package journal records the files changed by each tada command so the
+// last changes can be undone.
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/writer"
+)
+
+// DefaultKeep is the number of journal entries kept when none is configured.
+const DefaultKeep = 50
+
+// ErrEmpty is returned by Undo when there is nothing to undo.
+var ErrEmpty = errors.New("nothing to undo")
+
+// Change is a file changed by a command. Before and After are SHA-256
+// hashes of the content; Before is empty when the command created the file.
+// Backup is the writer's backup holding the previous content, if it made
+// one; otherwise the journal keeps a snapshot of it.
+type Change struct {
+	Path   string `json:"path"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after"`
+	Backup string `json:"backup,omitempty"`
+}
+
+// Entry is a command run that changed files.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Changes []Change  `json:"changes"`
+}
+
+// Journal is a directory, usually .tada next to the input file, holding
+// journal.jsonl with one entry per line, oldest first, and in snapshots/,
+// named by hash, the previous content of changed files without a backup.
+type Journal struct {
+	Dir  string
+	Keep int // entries kept, oldest are dropped first
+}
+
+// Recorder adds the changes of one command run to the journal.
+type Recorder struct {
+	journal Journal
+	entry   Entry
+}
+
+// Recorder starts an entry for a command run at now. Nothing is written
+// until the first change is recorded.
+func (j Journal) Recorder(command string, now time.Time) *Recorder {
+	return &Recorder{journal: j, entry: Entry{Time: now, Command: command}}
+}
+
+// Record saves the entry with the change of path. before is nil when the
+// file did not exist; its content is read back from backup, or from a
+// snapshot stored when there is no backup. It has the signature of a
+// writer.Observer.
+func (r *Recorder) Record(path string, before, after []byte, backup string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	change := Change{Path: path, After: hash(after)}
+	if before != nil {
+		change.Before = hash(before)
+		if backup != "" {
+			if change.Backup, err = filepath.Abs(backup); err != nil {
+				return err
+			}
+		} else if err := r.journal.saveSnapshot(change.Before, before); err != nil {
+			return err
+		}
+	}
+
+	// A file written twice by one command keeps its first previous content
+	replaced := false
+	for i := range r.entry.Changes {
+		if r.entry.Changes[i].Path == path {
+			r.entry.Changes[i].After = change.After
+			replaced = true
+		}
+	}
+	if !replaced {
+		r.entry.Changes = append(r.entry.Changes, change)
+	}
+
+	entries, err := r.journal.Entries()
+	if err != nil {
+		return err
+	}
+	if n := len(entries); n > 0 && entries[n-1].Time.Equal(r.entry.Time) && entries[n-1].Command == r.entry.Command {
+		entries[n-1] = r.entry
+	} else {
+		entries = append(entries, r.entry)
+	}
+	if r.journal.Keep > 0 && len(entries) > r.journal.Keep {
+		entries = entries[len(entries)-r.journal.Keep:]
+	}
+	return r.journal.save(entries)
+}
+
+// Entries returns the journal entries, oldest first.
+func (j Journal) Entries() ([]Entry, error) {
+	file, err := os.Open(j.path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid journal %s: %w", j.path(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// Undo restores the files changed by the last entry to their previous
+// content, removing files the command created, and drops the entry. Unless
+// force is set, it refuses when a file was changed since.
+func (j Journal) Undo(force bool) (Entry, error) {
+	entries, err := j.Entries()
+	if err != nil {
+		return Entry{}, err
+	}
+	if len(entries) == 0 {
+		return Entry{}, ErrEmpty
+	}
+	entry := entries[len(entries)-1]
+
+	if !force {
+		for _, change := range entry.Changes {
+			current, err := os.ReadFile(change.Path)
+			if err != nil && !os.IsNotExist(err) {
+				return entry, err
+			}
+			if err != nil || hash(current) != change.After {
+				return entry, fmt.Errorf("%s has changed since %q, use --force to undo anyway", change.Path, entry.Command)
+			}
+		}
+	}
+
+	for i := len(entry.Changes) - 1; i >= 0; i-- {
+		change := entry.Changes[i]
+		if change.Before == "" {
+			if err := os.Remove(change.Path); err != nil && !os.IsNotExist(err) {
+				return entry, err
+			}
+			continue
+		}
+
+		content, err := j.previous(change)
+		if err != nil {
+			return entry, err
+		}
+		if err := writer.WriteFileAtomic(change.Path, content); err != nil {
+			return entry, err
+		}
+	}
+
+	return entry, j.save(entries[:len(entries)-1])
+}
+
+// previous returns the content of a changed file before the change.
+func (j Journal) previous(change Change) ([]byte, error) {
+	path := j.snapshotPath(change.Before)
+	if change.Backup != "" {
+		path = change.Backup
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("missing previous content of %s: %w", change.Path, err)
+	}
+	if hash(content) != change.Before {
+		return nil, fmt.Errorf("previous content of %s in %s has changed", change.Path, path)
+	}
+	return content, nil
+}
+
+// save writes the entries and removes snapshots they no longer refer to.
+// Entries up to the last one whose backup was removed, e.g. by the rotation
+// of backups, cannot be undone and are dropped.
+func (j Journal) save(entries []Entry) error {
+	if err := os.MkdirAll(j.Dir, 0755); err != nil {
+		return err
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !j.backupsExist(entries[i]) {
+			entries = entries[i+1:]
+			break
+		}
+	}
+
+	var buf bytes.Buffer
+	used := make(map[string]bool)
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteString("\n")
+
+		for _, change := range entry.Changes {
+			if change.Backup == "" {
+				used[change.Before] = true
+			}
+		}
+	}
+
+	if err := writer.WriteFileAtomic(j.path(), buf.Bytes()); err != nil {
+		return err
+	}
+
+	snapshots, err := os.ReadDir(filepath.Join(j.Dir, "snapshots"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		if !used[snapshot.Name()] {
+			os.Remove(filepath.Join(j.Dir, "snapshots", snapshot.Name()))
+		}
+	}
+	return nil
+}
+
+func (j Journal) backupsExist(entry Entry) bool {
+	for _, change := range entry.Changes {
+		if change.Backup == "" {
+			continue
+		}
+		if _, err := os.Stat(change.Backup); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (j Journal) saveSnapshot(sum string, content []byte) error {
+	path := j.snapshotPath(sum)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writer.WriteFileAtomic(path, content)
+}
+
+func (j Journal) path() string {
+	return filepath.Join(j.Dir, "journal.jsonl")
+}
+
+func (j Journal) snapshotPath(sum string) string {
+	return filepath.Join(j.Dir, "snapshots", sum)
+}
+
+func hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,140 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordAndUndo(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.md")
+	report := filepath.Join(dir, "report.md")
+	j := Journal{Dir: filepath.Join(dir, ".tada"), Keep: 10}
+	now := time.Date(2025, 9, 14, 10, 0, 0, 0, time.UTC)
+
+	write := func(r *Recorder, path, content string) {
+		t.Helper()
+		before, err := os.ReadFile(path)
+		if err != nil {
+			before = nil
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Record(path, before, []byte(content), ""); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	os.WriteFile(input, []byte("v1"), 0644)
+	tidy := j.Recorder("tada tidy", now)
+	write(tidy, input, "v2")
+	write(tidy, input, "v3")
+
+	gen := j.Recorder("tada gen", now.Add(time.Minute))
+	write(gen, input, "v4")
+	write(gen, report, "report")
+
+	entries, err := j.Entries()
+	if err != nil {
+		t.Fatalf("Entries failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Command != "tada tidy" || len(entries[0].Changes) != 1 || len(entries[1].Changes) != 2 {
+		t.Fatalf("Unexpected entries %+v", entries)
+	}
+
+	// Undo gen: input back to v3, report removed
+	entry, err := j.Undo(false)
+	if err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if entry.Command != "tada gen" {
+		t.Errorf("Expected to undo 'tada gen', got '%s'", entry.Command)
+	}
+	if content, _ := os.ReadFile(input); string(content) != "v3" {
+		t.Errorf("Expected input v3, got %q", content)
+	}
+	if _, err := os.Stat(report); !os.IsNotExist(err) {
+		t.Errorf("Expected report to be removed")
+	}
+
+	// Undo tidy after a manual edit needs force
+	os.WriteFile(input, []byte("edited"), 0644)
+	if _, err := j.Undo(false); err == nil || !strings.Contains(err.Error(), "changed since") {
+		t.Errorf("Expected undo of an edited file to fail, got %v", err)
+	}
+	if _, err := j.Undo(true); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if content, _ := os.ReadFile(input); string(content) != "v1" {
+		t.Errorf("Expected input v1, got %q", content)
+	}
+
+	if _, err := j.Undo(false); err != ErrEmpty {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+	if snapshots, _ := os.ReadDir(filepath.Join(j.Dir, "snapshots")); len(snapshots) != 0 {
+		t.Errorf("Expected unused snapshots to be removed, got %d", len(snapshots))
+	}
+}
+
+func TestJournalKeep(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.md")
+	j := Journal{Dir: filepath.Join(dir, ".tada"), Keep: 2}
+	now := time.Date(2025, 9, 14, 10, 0, 0, 0, time.UTC)
+
+	for i, content := range []string{"a", "b", "c"} {
+		if err := j.Recorder("tada tidy", now.Add(time.Duration(i)*time.Second)).Record(input, []byte{}, []byte(content), ""); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	entries, _ := j.Entries()
+	if len(entries) != 2 || !entries[0].Time.Equal(now.Add(time.Second)) {
+		t.Errorf("Expected the 2 newest entries, got %+v", entries)
+	}
+}
+
+func TestRecordWithBackup(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.md")
+	j := Journal{Dir: filepath.Join(dir, ".tada"), Keep: 10}
+	now := time.Date(2025, 9, 14, 10, 0, 0, 0, time.UTC)
+
+	// Each version is kept once, in its backup
+	os.WriteFile(input, []byte("v1"), 0644)
+	backups := make([]string, 2)
+	for i, content := range []string{"v2", "v3"} {
+		before, _ := os.ReadFile(input)
+		backups[i] = filepath.Join(dir, fmt.Sprintf("input.md.%d", i))
+		os.WriteFile(backups[i], before, 0644)
+		os.WriteFile(input, []byte(content), 0644)
+		if err := j.Recorder("tada tidy", now.Add(time.Duration(i)*time.Second)).Record(input, before, []byte(content), backups[i]); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(j.Dir, "snapshots")); !os.IsNotExist(err) {
+		t.Errorf("Expected no snapshots next to backups")
+	}
+
+	if _, err := j.Undo(false); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if content, _ := os.ReadFile(input); string(content) != "v2" {
+		t.Errorf("Expected input v2, got %q", content)
+	}
+
+	// An entry whose backup was rotated out cannot be undone and is dropped
+	os.Remove(backups[0])
+	if err := j.Recorder("tada tidy", now.Add(time.Minute)).Record(input, []byte("v2"), []byte("v4"), ""); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if entries, _ := j.Entries(); len(entries) != 1 || !entries[0].Time.Equal(now.Add(time.Minute)) {
+		t.Errorf("Expected only the newest entry, got %+v", entries)
+	}
+}
@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 type TaskStatus string
 
@@ -10,6 +14,32 @@ const (
 	StatusDone       TaskStatus = "[x]"
 )
 
+// Name returns a readable name for the status: todo, in-progress or done.
+func (s TaskStatus) Name() string {
+	switch s {
+	case StatusDone:
+		return "done"
+	case StatusInProgress:
+		return "in-progress"
+	default:
+		return "todo"
+	}
+}
+
+// ParseStatusName maps a readable status name to a TaskStatus.
+func ParseStatusName(name string) (TaskStatus, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "todo", "open", "[ ]":
+		return StatusTodo, nil
+	case "in-progress", "progress", "doing", "started", "[-]":
+		return StatusInProgress, nil
+	case "done", "completed", "[x]":
+		return StatusDone, nil
+	default:
+		return "", fmt.Errorf("unknown status %q, expected todo, in-progress or done", name)
+	}
+}
+
 type SectionName string
 
 const (
@@ -19,9 +49,134 @@ const (
 	SectionDone     SectionName = "Done"
 )
 
+// sectionTitles holds header text configured for the standard sections.
+var sectionTitles = map[SectionName]string{}
+
+// SetSectionTitles changes the "## " header text used for the standard
+// sections, e.g. {SectionBacklog: "Inbox"}. The default names stay
+// recognised when parsing.
+func SetSectionTitles(titles map[SectionName]string) {
+	sectionTitles = make(map[SectionName]string, len(titles))
+	for name, title := range titles {
+		sectionTitles[name] = title
+	}
+}
+
+// standardSections lists the standard sections in the order titles are
+// looked up.
+var standardSections = []SectionName{SectionBacklog, SectionArchives, SectionTodo, SectionDone}
+
+// SectionFromTitle maps "## " header text to a section name. Configured
+// titles take precedence over the default names.
+func SectionFromTitle(title string) SectionName {
+	for _, name := range standardSections {
+		if configured := sectionTitles[name]; configured != "" && configured == title {
+			return name
+		}
+	}
+
+	for _, name := range standardSections {
+		if string(name) == title {
+			return name
+		}
+	}
+	return SectionName(title)
+}
+
+// Title returns the header text written for the section.
+func (n SectionName) Title() string {
+	if title, ok := sectionTitles[n]; ok && title != "" {
+		return title
+	}
+	return string(n)
+}
+
+// HasDateGroups reports whether tasks in the section are grouped under
+// "### YYYY-MM-DD" date headers.
+func (n SectionName) HasDateGroups() bool {
+	return n == SectionTodo || n == SectionDone
+}
+
+// Source is the verbatim markdown a parsed element came from. The writer
+// re-emits it byte-for-byte as long as the element has not changed, so free
+// text, blank lines and headings survive a rewrite.
+type Source struct {
+	Leading     []string // unrecognised lines placed before the element
+	Lines       []string // the element's own lines
+	Trailing    []string // unrecognised lines placed after the element
+	Fingerprint string   // Task.Fingerprint at parse time (tasks only)
+
+	// How the file's lines end (sections only)
+	CRLF           bool // lines end with "\r\n"
+	NoFinalNewline bool // the last line has no line ending
+}
+
+// Position is where a task or subtask was found in the input markdown. Line
+// numbers are 1-based; a zero Position means the element was not parsed.
+type Position struct {
+	File       string
+	Section    SectionName
+	DateHeader string // YYYY-MM-DD of the enclosing date header, if any
+	StartLine  int
+	EndLine    int
+}
+
+// IsValid reports whether the position points into a file.
+func (p Position) IsValid() bool {
+	return p.StartLine > 0
+}
+
+func (p Position) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%d", p.StartLine)
+	}
+	return fmt.Sprintf("%s:%d", p.File, p.StartLine)
+}
+
+// Subtask is a checklist item under a task. Items indented under it are
+// its children, to any depth.
 type Subtask struct {
-	Status  TaskStatus
-	Content string
+	Status   TaskStatus
+	Content  string
+	Children []Subtask
+	Position Position
+}
+
+// SubtaskBuilder nests subtasks read in document order by their
+// indentation: a subtask becomes a child of the closest previous subtask
+// indented less, or a top-level subtask if there is none.
+type SubtaskBuilder struct {
+	indents []int // indentation of the last subtask at each depth
+}
+
+// Add adds subtask, indented by indent columns, to tree.
+func (b *SubtaskBuilder) Add(tree *[]Subtask, indent int, subtask Subtask) {
+	for len(b.indents) > 0 && b.indents[len(b.indents)-1] >= indent {
+		b.indents = b.indents[:len(b.indents)-1]
+	}
+
+	// The parent is the last subtask at each open depth
+	siblings := tree
+	for range b.indents {
+		last := &(*siblings)[len(*siblings)-1]
+		siblings = &last.Children
+	}
+
+	*siblings = append(*siblings, subtask)
+	b.indents = append(b.indents, indent)
+}
+
+// WalkSubtasks calls fn for every subtask in the tree, depth first, with its
+// depth starting at 0 for top-level subtasks.
+func WalkSubtasks(subtasks []Subtask, fn func(subtask *Subtask, depth int)) {
+	var walk func(subtasks []Subtask, depth int)
+	walk = func(subtasks []Subtask, depth int) {
+		for i := range subtasks {
+			fn(&subtasks[i], depth)
+			walk(subtasks[i].Children, depth+1)
+		}
+	}
+	walk(subtasks, 0)
 }
 
 // Task represents a single, raw task as it appears in the input markdown.
@@ -34,9 +189,61 @@ type Task struct {
 	EndDate     *time.Time
 	Description []string
 	SubTasks    []Subtask
+	Reopened    bool // Todo entry recorded by "tada reopen"
+	Position    Position
+	Source      *Source
+}
+
+// Fingerprint returns a string identifying the task's content. Two tasks with
+// the same fingerprint are written out identically.
+func (t Task) Fingerprint() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s|%s|%s|%s|%s|%s", t.Status, t.ID, t.Project, t.Title,
+		FormatDate(t.StartDate), FormatDate(t.EndDate))
+	if t.Reopened {
+		b.WriteString("|reopened")
+	}
+	for _, desc := range t.Description {
+		fmt.Fprintf(&b, "\n  %s", desc)
+	}
+	WalkSubtasks(t.SubTasks, func(subtask *Subtask, depth int) {
+		fmt.Fprintf(&b, "\n%s- %s %s", strings.Repeat("  ", depth+1), subtask.Status, subtask.Content)
+	})
+	return b.String()
+}
+
+// DateGroup is a "### YYYY-MM-DD" header found in a dated section.
+type DateGroup struct {
+	Date   string // YYYY-MM-DD
+	Label  string // text after the date, usually the day name
+	Source *Source
 }
 
 type Section struct {
-	Name  SectionName
-	Tasks []Task
+	Name   SectionName
+	Tasks  []Task
+	Groups []DateGroup
+	Source *Source
+}
+
+// FormatDate formats a date as YYYY-MM-DD, or "" when it is nil.
+func FormatDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
+
+// FormatDateRange formats a task's dates as "2025-09-13", or
+// "2025-09-13 - 2025-09-14" when they span several days; "" without a
+// start date.
+func FormatDateRange(startDate, endDate *time.Time) string {
+	switch {
+	case startDate == nil:
+		return ""
+	case endDate == nil || startDate.Equal(*endDate):
+		return startDate.Format("2006-01-02")
+	default:
+		return startDate.Format("2006-01-02") + " - " + endDate.Format("2006-01-02")
+	}
 }
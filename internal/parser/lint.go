@@ -0,0 +1,272 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a problem found in an input file, pointing at a 1-based line
+// and column.
+type Diagnostic struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s", d.File, d.Line, d.Column, d.Severity, d.Message)
+}
+
+var (
+	statusMarkerRegex = regexp.MustCompile(`^(\s*)-\s\[(.)\]\s`)
+	dateLikeRegex     = regexp.MustCompile(`^\d`)
+)
+
+type reportFunc func(line, column int, severity Severity, code, format string, args ...any)
+
+// lintTask is a task line seen while linting, kept until the whole file is
+// read so Todo/Done entries can be checked against the Backlog.
+type lintTask struct {
+	section model.SectionName
+	id      string
+	line    int
+	column  int
+}
+
+func LintFile(path string) ([]Diagnostic, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return []Diagnostic{}, fmt.Errorf("failed to open file %s: %w", path, err)
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	return LintContent(path, scanner)
+}
+
+// LintContent checks the markdown read from scanner for malformed lines and
+// tasks that cannot be linked across sections. path is only used to label
+// the diagnostics.
+func LintContent(path string, scanner *bufio.Scanner) ([]Diagnostic, error) {
+	diagnostics := []Diagnostic{}
+	var report reportFunc = func(line, column int, severity Severity, code, format string, args ...any) {
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     path,
+			Line:     line,
+			Column:   column,
+			Severity: severity,
+			Code:     code,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	var tasks []lintTask
+	var section model.SectionName
+	inSection := false
+	inTask := false
+	lineNo := 0
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNo++
+
+		// Status markers are checked first, the line would otherwise be
+		// taken for plain text or a description
+		if matches := statusMarkerRegex.FindStringSubmatchIndex(line); matches != nil {
+			marker := line[matches[4]:matches[5]]
+			if marker != " " && marker != "x" && marker != "-" {
+				report(lineNo, matches[4]+1, SeverityError, "unknown-status",
+					"unknown status marker [%s], expected [ ], [x] or [-]", marker)
+				continue
+			}
+		}
+
+		lineType, extractedValue := checkLineType(line)
+
+		switch lineType {
+		case LineSectionHeader:
+			section = sectionName(extractedValue)
+			inSection = true
+			inTask = false
+		case LineDateHeader:
+			if _, err := time.Parse("2006-01-02", extractedValue); err != nil {
+				report(lineNo, 5, SeverityError, "invalid-date",
+					"invalid date header %q", extractedValue)
+			}
+			inTask = false
+		case LineTask:
+			if !inSection {
+				report(lineNo, 1, SeverityWarning, "task-outside-section",
+					"task before any section header is ignored")
+				continue
+			}
+			inTask = true
+
+			id, column := lintTaskComment(line, lineNo, report)
+			tasks = append(tasks, lintTask{section: section, id: id, line: lineNo, column: column})
+		case LineSubtask:
+			if !inTask {
+				report(lineNo, len(line)-len(strings.TrimLeft(line, " \t"))+1, SeverityError,
+					"orphan-subtask", "subtask before any task is ignored")
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return diagnostics, err
+	}
+
+	lintTaskLinks(tasks, report)
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Line != diagnostics[j].Line {
+			return diagnostics[i].Line < diagnostics[j].Line
+		}
+		return diagnostics[i].Column < diagnostics[j].Column
+	})
+
+	return diagnostics, nil
+}
+
+// lintTaskComment checks the dates in a task comment and returns the task ID
+// with its column, if any. With Obsidian Tasks markers enabled, the dates
+// in the title are checked too and a 🆔 marker stands in for a missing ID.
+func lintTaskComment(line string, lineNo int, report reportFunc) (string, int) {
+	loc := taskRegex.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return "", 0
+	}
+
+	var id string
+	var column int
+	if loc[6] >= 0 {
+		id, column = lintCommentParts(line, loc[6], loc[7], lineNo, report)
+	}
+	if obsidianTasks {
+		// The comment takes precedence, as in the parser
+		markerID, markerColumn := lintObsidianMarkers(line, loc[4], loc[5], lineNo, report)
+		if id == "" {
+			id, column = markerID, markerColumn
+		}
+	}
+	return id, column
+}
+
+// lintObsidianMarkers checks the marker dates in line[start:end], a task
+// title, and returns the 🆔 marker value with its column, if any.
+func lintObsidianMarkers(line string, start, end, lineNo int, report reportFunc) (string, int) {
+	title := line[start:end]
+	for _, match := range obsidianDateRegex.FindAllStringSubmatchIndex(title, -1) {
+		value := title[match[4]:match[5]]
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			report(lineNo, start+match[4]+1, SeverityError, "invalid-date",
+				"unparseable date %q", value)
+		}
+	}
+
+	if match := obsidianIDRegex.FindStringSubmatchIndex(title); match != nil {
+		return title[match[2]:match[3]], start + match[0] + 1
+	}
+	return "", 0
+}
+
+// lintCommentParts checks the dates in the comment line[start:end] and
+// returns the task ID with its column, if any.
+func lintCommentParts(line string, start, end, lineNo int, report reportFunc) (string, int) {
+	var id string
+	var idColumn int
+
+	offset := start
+	for _, part := range strings.Split(line[start:end], "|") {
+		column := offset + len(part) - len(strings.TrimLeft(part, " ")) + 1
+		offset += len(part) + 1
+		part = strings.TrimSpace(part)
+
+		switch {
+		case strings.HasPrefix(part, "@"):
+		case strings.HasPrefix(part, "#"):
+			id = strings.TrimSpace(part[1:])
+			idColumn = column
+		case strings.Contains(part, " - "):
+			dates := strings.Split(part, " - ")
+			if len(dates) != 2 {
+				report(lineNo, column, SeverityError, "invalid-date",
+					"unparseable date range %q", part)
+				continue
+			}
+			start, startErr := time.Parse("2006-01-02", strings.TrimSpace(dates[0]))
+			end, endErr := time.Parse("2006-01-02", strings.TrimSpace(dates[1]))
+			if startErr != nil || endErr != nil {
+				report(lineNo, column, SeverityError, "invalid-date",
+					"unparseable date range %q", part)
+			} else if end.Before(start) {
+				report(lineNo, column, SeverityError, "end-before-start",
+					"date range %q ends before it starts", part)
+			}
+		case strings.HasSuffix(part, " -"):
+			start := strings.TrimSpace(strings.TrimSuffix(part, " -"))
+			if _, err := time.Parse("2006-01-02", start); err != nil {
+				report(lineNo, column, SeverityError, "invalid-date",
+					"unparseable date range %q", part)
+			}
+		case dateLikeRegex.MatchString(part):
+			if _, err := time.Parse("2006-01-02", part); err != nil {
+				report(lineNo, column, SeverityError, "invalid-date",
+					"unparseable date %q", part)
+			}
+		}
+	}
+
+	return id, idColumn
+}
+
+// lintTaskLinks reports duplicate Backlog IDs and Todo/Done tasks that are
+// not linked to a Backlog entry.
+func lintTaskLinks(tasks []lintTask, report reportFunc) {
+	backlog := make(map[string]int)
+
+	for _, task := range tasks {
+		if task.section != model.SectionBacklog || task.id == "" {
+			continue
+		}
+		if first, exists := backlog[task.id]; exists {
+			report(task.line, task.column, SeverityError, "duplicate-id",
+				"duplicate task ID #%s in Backlog (first defined on line %d)", task.id, first)
+			continue
+		}
+		backlog[task.id] = task.line
+	}
+
+	for _, task := range tasks {
+		if !task.section.HasDateGroups() {
+			continue
+		}
+		if task.id == "" {
+			report(task.line, 1, SeverityWarning, "missing-id",
+				"task in %s has no #id and is not linked to Backlog", task.section)
+			continue
+		}
+		if _, exists := backlog[task.id]; !exists {
+			report(task.line, task.column, SeverityError, "unknown-id",
+				"task ID #%s in %s has no Backlog entry", task.id, task.section)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestLintContent(t *testing.T) {
+	input := `  - [ ] orphan subtask
+## Backlog
+- [ ] Task <!-- @hrm|#1 -->
+- [ ] Duplicate <!-- @hrm|#1|2025-09-12 - 2025-09-10 -->
+- [?] Unknown status <!-- @hrm|#3 -->
+
+## Todo
+### 2025-13-01 - Bad
+- [ ] Not in backlog <!-- @hrm|#9|2025-9-1 -->
+- [ ] Without ID
+- [x] Linked <!-- @hrm|#1 -->`
+
+	diagnostics, err := LintContent("input.md", bufio.NewScanner(strings.NewReader(input)))
+	if err != nil {
+		t.Fatalf("LintContent failed: %v", err)
+	}
+
+	expected := []struct {
+		line     int
+		column   int
+		severity Severity
+		code     string
+	}{
+		{1, 3, SeverityError, "orphan-subtask"},
+		{4, 27, SeverityError, "duplicate-id"},
+		{4, 30, SeverityError, "end-before-start"},
+		{5, 4, SeverityError, "unknown-status"},
+		{8, 5, SeverityError, "invalid-date"},
+		{9, 32, SeverityError, "unknown-id"},
+		{9, 35, SeverityError, "invalid-date"},
+		{10, 1, SeverityWarning, "missing-id"},
+	}
+
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("Expected %d diagnostics, got %d: %v", len(expected), len(diagnostics), diagnostics)
+	}
+
+	for i, want := range expected {
+		got := diagnostics[i]
+		if got.Line != want.line || got.Column != want.column || got.Severity != want.severity || got.Code != want.code {
+			t.Errorf("Expected diagnostic %d to be %d:%d %s %s, got %d:%d %s %s",
+				i, want.line, want.column, want.severity, want.code,
+				got.Line, got.Column, got.Severity, got.Code)
+		}
+	}
+
+	if diagnostics[0].String() != "input.md:1:3: error: subtask before any task is ignored" {
+		t.Errorf("Unexpected diagnostic string '%s'", diagnostics[0].String())
+	}
+}
+
+func TestLintContentClean(t *testing.T) {
+	input := `## Backlog
+- [ ] Task <!-- @hrm|#1|2025-09-10 - 2025-09-12 -->
+
+## Done
+### 2025-09-12 - Jum'at
+- [x] Task <!-- @hrm|#1 -->`
+
+	diagnostics, err := LintContent("input.md", bufio.NewScanner(strings.NewReader(input)))
+	if err != nil {
+		t.Fatalf("LintContent failed: %v", err)
+	}
+
+	if len(diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics, got %v", diagnostics)
+	}
+}
+
+func TestLintContentObsidian(t *testing.T) {
+	input := `## Backlog
+- [ ] Call client 🛫 2025-13-01 🆔 42
+
+## Todo
+### 2025-09-12 - Jumat
+- [-] Call client 🆔 42
+- [ ] Review 🆔 7`
+
+	SetObsidianTasks(true)
+	defer SetObsidianTasks(false)
+
+	diagnostics, err := LintContent("input.md", bufio.NewScanner(strings.NewReader(input)))
+	if err != nil {
+		t.Fatalf("LintContent failed: %v", err)
+	}
+
+	lines := strings.Split(input, "\n")
+	expected := []struct {
+		line   int
+		column int
+		code   string
+	}{
+		{2, strings.Index(lines[1], "2025-13-01") + 1, "invalid-date"},
+		{7, strings.Index(lines[6], "🆔") + 1, "unknown-id"},
+	}
+
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("Expected %d diagnostics, got %d: %v", len(expected), len(diagnostics), diagnostics)
+	}
+	for i, want := range expected {
+		if got := diagnostics[i]; got.Line != want.line || got.Column != want.column || got.Code != want.code {
+			t.Errorf("Expected diagnostic %d to be %d:%d %s, got %d:%d %s",
+				i, want.line, want.column, want.code, got.Line, got.Column, got.Code)
+		}
+	}
+}
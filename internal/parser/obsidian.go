@@ -0,0 +1,131 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+)
+
+var (
+	obsidianDateRegex     = regexp.MustCompile(`(🛫|⏳|➕|📅|📆|🗓|✅|❌)\x{FE0F}?\s*(\d{4}-\d{2}-\d{2})`)
+	obsidianIDRegex       = regexp.MustCompile(`🆔\x{FE0F}?\s*([A-Za-z0-9_-]+)`)
+	obsidianPriorityRegex = regexp.MustCompile(`(🔺|⏫|🔼|🔽|⏬)\x{FE0F}?`)
+)
+
+var obsidianPriorities = map[string]string{
+	"🔺": "highest",
+	"⏫": "high",
+	"🔼": "medium",
+	"🔽": "low",
+	"⏬": "lowest",
+}
+
+// obsidianTasks enables Obsidian Tasks markers in task titles.
+var obsidianTasks bool
+
+// SetObsidianTasks makes the parser read the emoji markers of the Obsidian
+// Tasks plugin in task titles, e.g. "Call client 🛫 2025-01-15 ✅ 2025-01-18".
+// Values from a <!-- --> comment take precedence.
+func SetObsidianTasks(enabled bool) {
+	obsidianTasks = enabled
+}
+
+// ObsidianMarkers are the Obsidian Tasks fields found in a task title.
+type ObsidianMarkers struct {
+	ID        string     // 🆔
+	Start     *time.Time // 🛫
+	Scheduled *time.Time // ⏳
+	Created   *time.Time // ➕
+	Due       *time.Time // 📅
+	Done      *time.Time // ✅
+	Cancelled *time.Time // ❌
+	Priority  string     // highest, high, medium, low or lowest
+}
+
+// ParseObsidianMarkers removes the Obsidian Tasks markers from a title and
+// returns the cleaned title with the markers found. Recurrence, dependency
+// and other markers stay in the title.
+func ParseObsidianMarkers(title string) (string, ObsidianMarkers) {
+	var markers ObsidianMarkers
+
+	title = obsidianDateRegex.ReplaceAllStringFunc(title, func(match string) string {
+		parts := obsidianDateRegex.FindStringSubmatch(match)
+		date, err := time.Parse("2006-01-02", parts[2])
+		if err != nil {
+			return match
+		}
+
+		switch parts[1] {
+		case "🛫":
+			markers.Start = &date
+		case "⏳":
+			markers.Scheduled = &date
+		case "➕":
+			markers.Created = &date
+		case "✅":
+			markers.Done = &date
+		case "❌":
+			markers.Cancelled = &date
+		default:
+			markers.Due = &date
+		}
+		return ""
+	})
+
+	title = obsidianIDRegex.ReplaceAllStringFunc(title, func(match string) string {
+		markers.ID = obsidianIDRegex.FindStringSubmatch(match)[1]
+		return ""
+	})
+
+	title = obsidianPriorityRegex.ReplaceAllStringFunc(title, func(match string) string {
+		markers.Priority = obsidianPriorities[obsidianPriorityRegex.FindStringSubmatch(match)[1]]
+		return ""
+	})
+
+	return strings.Join(strings.Fields(title), " "), markers
+}
+
+// Apply fills the task fields that are still empty, as fill does. The due
+// date and priority have no task field and become "Due: 2025-01-20" and
+// "Priority: high" description lines.
+func (m ObsidianMarkers) Apply(task *model.Task) {
+	m.fill(task)
+
+	if m.Due != nil {
+		task.Description = append(task.Description, "Due: "+m.Due.Format("2006-01-02"))
+	}
+	if m.Priority != "" {
+		task.Description = append(task.Description, "Priority: "+m.Priority)
+	}
+}
+
+// fill sets the task fields that are still empty: the ID, the start date
+// from 🛫, ⏳ or ➕ (in that order), and the end date from ✅ or ❌.
+func (m ObsidianMarkers) fill(task *model.Task) {
+	if task.ID == "" {
+		task.ID = m.ID
+	}
+
+	if task.StartDate == nil {
+		for _, date := range []*time.Time{m.Start, m.Scheduled, m.Created} {
+			if date != nil {
+				task.StartDate = date
+				break
+			}
+		}
+	}
+
+	if task.EndDate == nil {
+		if m.Done != nil {
+			task.EndDate = m.Done
+		} else if m.Cancelled != nil {
+			task.EndDate = m.Cancelled
+		}
+	}
+
+	if task.StartDate == nil && task.EndDate != nil {
+		task.StartDate = task.EndDate
+	}
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseObsidianMarkers(t *testing.T) {
+	title, markers := ParseObsidianMarkers("Call client 🛫 2025-01-15 📅 2025-01-20 ✅ 2025-01-18 ⏫ 🆔 abc 🔁 every week")
+
+	if title != "Call client 🔁 every week" {
+		t.Errorf("Expected markers to be removed, got '%s'", title)
+	}
+	if markers.ID != "abc" || markers.Priority != "high" {
+		t.Errorf("Expected ID 'abc' and priority 'high', got '%s' and '%s'", markers.ID, markers.Priority)
+	}
+	if !timePtrEqual(markers.Start, timePtr(2025, 1, 15)) || !timePtrEqual(markers.Due, timePtr(2025, 1, 20)) || !timePtrEqual(markers.Done, timePtr(2025, 1, 18)) {
+		t.Errorf("Unexpected dates: start %v, due %v, done %v", markers.Start, markers.Due, markers.Done)
+	}
+}
+
+func TestParseTaskLineObsidian(t *testing.T) {
+	line := "- [x] Call client ⏳ 2025-01-14 ✅ 2025-01-18 🔽 <!-- @crm|#7 -->"
+
+	task := parseTaskLine(line, nil)
+	if !strings.Contains(task.Title, "✅") {
+		t.Errorf("Expected markers to stay in the title when disabled, got '%s'", task.Title)
+	}
+
+	SetObsidianTasks(true)
+	defer SetObsidianTasks(false)
+
+	task = parseTaskLine(line, nil)
+	if task.Title != "Call client ⏳ 2025-01-14 ✅ 2025-01-18 🔽" || task.Project != "crm" || task.ID != "7" {
+		t.Errorf("Expected title with markers @crm #7, got '%s' @%s #%s", task.Title, task.Project, task.ID)
+	}
+	if !timePtrEqual(task.StartDate, timePtr(2025, 1, 14)) || !timePtrEqual(task.EndDate, timePtr(2025, 1, 18)) {
+		t.Errorf("Expected 2025-01-14 - 2025-01-18, got %v - %v", task.StartDate, task.EndDate)
+	}
+	if len(task.Description) != 0 {
+		t.Errorf("Expected no description lines, got %v", task.Description)
+	}
+
+	// Without a comment the 🆔 marker gives the ID
+	task = parseTaskLine("- [ ] Review 🛫 2025-01-15 🆔 x1", nil)
+	if task.ID != "x1" || !timePtrEqual(task.StartDate, timePtr(2025, 1, 15)) || task.EndDate != nil {
+		t.Errorf("Expected #x1 from 2025-01-15, got #%s from %v to %v", task.ID, task.StartDate, task.EndDate)
+	}
+
+	// The comment takes precedence over markers
+	task = parseTaskLine("- [ ] Review 🛫 2025-01-15 🆔 x1 <!-- #9|2025-01-10 -->", nil)
+	if task.ID != "9" || !timePtrEqual(task.StartDate, timePtr(2025, 1, 10)) {
+		t.Errorf("Expected #9 from 2025-01-10, got #%s from %v", task.ID, task.StartDate)
+	}
+}
@@ -24,7 +24,7 @@ const (
 
 var (
 	sectionHeaderRegex = regexp.MustCompile(`^##\s(.+?)\s*$`)
-	dateHeaderRegex    = regexp.MustCompile(`^###\s(\d{4}-\d{2}-\d{2})(?:\s.*)?$`)
+	dateHeaderRegex    = regexp.MustCompile(`^###\s(\d{4}-\d{2}-\d{2})(?:\s(.*))?$`)
 	taskRegex          = regexp.MustCompile(`^-\s\[( |x|-)\]\s(.+?)(?:\s<!--(.+?)-->)?$`)
 	subtaskRegex       = regexp.MustCompile(`^\s+-\s\[( |x|-)\]\s(.+)$`)
 	descriptionRegex   = regexp.MustCompile(`^\s+.+$`)
@@ -39,7 +39,19 @@ func ParseFile(path string) ([]model.Section, error) {
 	defer file.Close()
 
 	scanner := bufio.NewScanner(file)
-	return ParseContent(scanner)
+	sections, err := ParseContent(scanner)
+
+	for i := range sections {
+		for j := range sections[i].Tasks {
+			task := &sections[i].Tasks[j]
+			task.Position.File = path
+			model.WalkSubtasks(task.SubTasks, func(subtask *model.Subtask, depth int) {
+				subtask.Position.File = path
+			})
+		}
+	}
+
+	return sections, err
 }
 
 func ParseContent(scanner *bufio.Scanner) ([]model.Section, error) {
@@ -47,81 +59,191 @@ func ParseContent(scanner *bufio.Scanner) ([]model.Section, error) {
 	var currentSection *model.Section
 	var currentTask *model.Task
 	var currentDate *time.Time
+	var currentGroup string
+	var subtasks model.SubtaskBuilder
+
+	// Lines that carry no task data wait in pending until we know where they
+	// belong. tail is the source of the last element seen, which owns them.
+	var pending []string
+	var tail *model.Source
+	lineNo := 0
+
+	// Line endings are dropped from the lines but recorded, so the writer
+	// can end its lines the same way
+	var crlf, noFinalNewline bool
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if advance > 0 {
+			ended := data[advance-1] == '\n'
+			if lineNo == 0 && ended {
+				crlf = advance > 1 && data[advance-2] == '\r'
+			}
+			noFinalNewline = !ended
+		}
+		return advance, token, err
+	})
+
+	// release hands pending lines to the previous element. Before a header,
+	// the blank lines directly above it are returned as the header's leading
+	// lines instead, so they stay in place when tasks are added or removed.
+	release := func(beforeHeader bool) []string {
+		leading := pending
+		pending = nil
+		if tail == nil {
+			return leading
+		}
+
+		n := len(leading)
+		if beforeHeader {
+			for n > 0 && strings.TrimSpace(leading[n-1]) == "" {
+				n--
+			}
+		}
+		tail.Trailing = append(tail.Trailing, leading[:n]...)
+		return leading[n:]
+	}
 
-	sectionNameList := map[string]model.SectionName{
-		"Backlog":  model.SectionBacklog,
-		"Archives": model.SectionArchives,
-		"Todo":     model.SectionTodo,
-		"Done":     model.SectionDone,
+	saveTask := func() {
+		if currentTask != nil && currentSection != nil {
+			currentTask.Source.Fingerprint = currentTask.Fingerprint()
+			currentTask.Position.EndLine = currentTask.Position.StartLine + len(currentTask.Source.Lines) - 1
+			currentSection.Tasks = append(currentSection.Tasks, *currentTask)
+		}
+		currentTask = nil
 	}
 
 	for scanner.Scan() {
 		line := scanner.Text()
+		lineNo++
 		lineType, extractedValue := checkLineType(line)
 
 		switch lineType {
 		case LineSectionHeader:
+			leading := release(true)
+
 			// Save previous task before new section
+			saveTask()
 			if currentSection != nil {
-				if currentTask != nil {
-					currentSection.Tasks = append(currentSection.Tasks, *currentTask)
-				}
 				sections = append(sections, *currentSection)
 			}
 
-			name, ok := sectionNameList[extractedValue]
-			if !ok {
-				name = model.SectionName(extractedValue)
-			}
+			name := sectionName(extractedValue)
 
-			currentSection = &model.Section{Name: name}
-			currentTask = nil
-		case LineDateHeader:
-			// Save previous task before new date group
-			if currentTask != nil && currentSection != nil {
-				currentSection.Tasks = append(currentSection.Tasks, *currentTask)
-				currentTask = nil
+			currentSection = &model.Section{
+				Name:   name,
+				Source: &model.Source{Leading: leading, Lines: []string{line}},
 			}
-
+			currentGroup = ""
+			currentDate = nil
+			tail = currentSection.Source
+		case LineDateHeader:
 			if date, err := time.Parse("2006-01-02", extractedValue); err == nil {
 				currentDate = &date
 			}
+
+			// Date headers only group tasks in dated sections, elsewhere
+			// they are kept as plain text
+			if currentSection == nil || !currentSection.Name.HasDateGroups() {
+				saveTask()
+				pending = append(pending, line)
+				break
+			}
+
+			leading := release(true)
+
+			// Save previous task before new date group
+			saveTask()
+
+			group := model.DateGroup{
+				Date:   extractedValue,
+				Label:  parseDateLabel(line),
+				Source: &model.Source{Leading: leading, Lines: []string{line}},
+			}
+			currentSection.Groups = append(currentSection.Groups, group)
+			currentGroup = extractedValue
+			tail = group.Source
 		case LineTask:
-			// Save previous task
-			if currentTask != nil && currentSection != nil {
-				currentSection.Tasks = append(currentSection.Tasks, *currentTask)
+			if currentSection == nil {
+				pending = append(pending, line)
+				break
 			}
 
+			// Save previous task
+			release(false)
+			saveTask()
+
 			// Parse new task, passing the current date
 			task := parseTaskLine(line, currentDate)
+			task.Position = model.Position{
+				Section:    currentSection.Name,
+				DateHeader: currentGroup,
+				StartLine:  lineNo,
+				EndLine:    lineNo,
+			}
+			task.Source = &model.Source{Lines: []string{line}}
 			currentTask = &task
+			subtasks = model.SubtaskBuilder{}
+			tail = task.Source
 
-		case LineSubtask:
-			if currentTask != nil {
-				subtask := parseSubTaskLine(line)
-				currentTask.SubTasks = append(currentTask.SubTasks, subtask)
+		case LineSubtask, LineDescription:
+			if currentTask == nil {
+				pending = append(pending, line)
+				break
 			}
-		case LineDescription:
-			if currentTask != nil {
+
+			// Lines in between belong to the task body
+			currentTask.Source.Lines = append(currentTask.Source.Lines, pending...)
+			currentTask.Source.Lines = append(currentTask.Source.Lines, line)
+			pending = nil
+
+			if lineType == LineSubtask {
+				subtask := parseSubTaskLine(line)
+				subtask.Position = model.Position{
+					Section:    currentSection.Name,
+					DateHeader: currentGroup,
+					StartLine:  lineNo,
+					EndLine:    lineNo,
+				}
+				subtasks.Add(&currentTask.SubTasks, indentWidth(line), subtask)
+			} else {
 				currentTask.Description = append(currentTask.Description, extractedValue)
 			}
-		case LineUnknown:
-			// ignore
 		default:
-			// ignore
+			pending = append(pending, line)
 		}
 
 	}
 
 	// The last task and section
-	if currentTask != nil && currentSection != nil {
-		currentSection.Tasks = append(currentSection.Tasks, *currentTask)
-	}
+	release(false)
+	saveTask()
 	if currentSection != nil {
 		sections = append(sections, *currentSection)
 	}
 
-	return sections, nil
+	for i := range sections {
+		sections[i].Source.CRLF = crlf
+		sections[i].Source.NoFinalNewline = noFinalNewline
+	}
+
+	return sections, scanner.Err()
+}
+
+func sectionName(value string) model.SectionName {
+	return model.SectionFromTitle(value)
+}
+
+// parseDateLabel returns whatever the user wrote after the date of a date
+// header, e.g. "Senin" for "### 2025-09-15 - Senin".
+func parseDateLabel(line string) string {
+	matches := dateHeaderRegex.FindStringSubmatch(line)
+	if len(matches) < 3 {
+		return ""
+	}
+
+	label := strings.TrimSpace(matches[2])
+	label = strings.TrimSpace(strings.TrimPrefix(label, "-"))
+	return label
 }
 
 func parseTaskLine(line string, date *time.Time) model.Task {
@@ -154,14 +276,14 @@ func parseTaskLine(line string, date *time.Time) model.Task {
 
 	// Parse comment for project, ID, and dates
 	project, taskId, startDate, endDate := parseComment(comment)
-
-	// Use fallback date if no dates found in comment
-	if startDate == nil && date != nil {
-		startDate = date
-		endDate = date
+	reopened := false
+	for part := range strings.SplitSeq(comment, "|") {
+		if strings.TrimSpace(part) == "reopened" {
+			reopened = true
+		}
 	}
 
-	return model.Task{
+	task := model.Task{
 		ID:          taskId,
 		Title:       title,
 		Project:     project,
@@ -170,7 +292,23 @@ func parseTaskLine(line string, date *time.Time) model.Task {
 		EndDate:     endDate,
 		Description: []string{},
 		SubTasks:    []model.Subtask{},
+		Reopened:    reopened,
+	}
+
+	// Obsidian Tasks markers fill in what the comment leaves out. They stay
+	// in the title, so a rewritten task keeps them
+	if obsidianTasks {
+		_, markers := ParseObsidianMarkers(title)
+		markers.fill(&task)
 	}
+
+	// Use fallback date if no dates found in comment
+	if task.StartDate == nil && date != nil {
+		task.StartDate = date
+		task.EndDate = date
+	}
+
+	return task
 }
 
 func parseSubTaskLine(line string) model.Subtask {
@@ -197,6 +335,23 @@ func parseSubTaskLine(line string) model.Subtask {
 	}
 }
 
+// indentWidth returns the indentation of a line in columns, counting a tab
+// as four.
+func indentWidth(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
 func parseComment(comment string) (project string, taskId string, startDate *time.Time, endDate *time.Time) {
 	if comment == "" {
 		return
@@ -219,6 +374,11 @@ func parseComment(comment string) (project string, taskId string, startDate *tim
 					endDate = &end
 				}
 			}
+		} else if start, open := strings.CutSuffix(part, " -"); open {
+			// Open range: "2025-09-12 -", started but not ended
+			if date, err := time.Parse("2006-01-02", strings.TrimSpace(start)); err == nil {
+				startDate = &date
+			}
 		} else if matched, _ := regexp.MatchString(`^\d{4}-\d{2}-\d{2}$`, part); matched {
 			// Single date: "2025-09-12"
 			if date, err := time.Parse("2006-01-02", part); err == nil {
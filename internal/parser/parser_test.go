@@ -192,6 +192,45 @@ func TestParseContent(t *testing.T) {
 	}
 }
 
+func TestParseContentPositions(t *testing.T) {
+	input := `## Backlog
+- [ ] Task <!-- @crm|#1 -->
+  some description
+
+  - [ ] subtask
+
+## Done
+### 2025-09-12 - Jum'at
+- [x] Task <!-- @crm|#1 -->`
+
+	sections, err := ParseContent(bufio.NewScanner(strings.NewReader(input)))
+	if err != nil {
+		t.Fatalf("ParseContent failed: %v", err)
+	}
+
+	backlogTask := sections[0].Tasks[0]
+	expected := model.Position{Section: model.SectionBacklog, StartLine: 2, EndLine: 5}
+	if backlogTask.Position != expected {
+		t.Errorf("Expected backlog task position %+v, got %+v", expected, backlogTask.Position)
+	}
+
+	subtask := backlogTask.SubTasks[0]
+	expected = model.Position{Section: model.SectionBacklog, StartLine: 5, EndLine: 5}
+	if subtask.Position != expected {
+		t.Errorf("Expected subtask position %+v, got %+v", expected, subtask.Position)
+	}
+
+	if label := sections[1].Groups[0].Label; label != "Jum'at" {
+		t.Errorf("Expected date header label to be 'Jum'at', got '%s'", label)
+	}
+
+	doneTask := sections[1].Tasks[0]
+	expected = model.Position{Section: model.SectionDone, DateHeader: "2025-09-12", StartLine: 9, EndLine: 9}
+	if doneTask.Position != expected {
+		t.Errorf("Expected done task position %+v, got %+v", expected, doneTask.Position)
+	}
+}
+
 func TestParseComment(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -235,6 +274,12 @@ func TestParseComment(t *testing.T) {
 			startDate: timePtr(2025, 9, 11),
 			endDate:   timePtr(2025, 9, 12),
 		},
+		{
+			name:      "open date range",
+			comment:   "#125|2025-09-11 -",
+			taskId:    "125",
+			startDate: timePtr(2025, 9, 11),
+		},
 		{
 			name:      "date range only",
 			comment:   "2025-09-09 - 2025-09-10",
@@ -444,3 +489,51 @@ func timePtrEqual(a, b *time.Time) bool {
 	}
 	return a.Equal(*b)
 }
+
+func TestParseNestedSubtasks(t *testing.T) {
+	content := `## Backlog
+- [ ] API <!-- #1 -->
+  - [ ] auth
+    - [x] tokens
+        - [ ] refresh
+    - [ ] sessions
+  note between
+  - [-] docs
+	- [ ] tabbed
+- [ ] Next <!-- #2 -->
+    - [ ] deep first
+  - [ ] shallow
+`
+
+	sections, err := ParseContent(bufio.NewScanner(strings.NewReader(content)))
+	if err != nil {
+		t.Fatalf("ParseContent failed: %v", err)
+	}
+
+	var tree func(subtasks []model.Subtask) string
+	tree = func(subtasks []model.Subtask) string {
+		var parts []string
+		for _, subtask := range subtasks {
+			part := subtask.Content
+			if len(subtask.Children) > 0 {
+				part += "(" + tree(subtask.Children) + ")"
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, " ")
+	}
+
+	tasks := sections[0].Tasks
+	if result := tree(tasks[0].SubTasks); result != "auth(tokens(refresh) sessions) docs(tabbed)" {
+		t.Errorf("Unexpected subtask tree %s", result)
+	}
+	if result := tree(tasks[1].SubTasks); result != "deep first shallow" {
+		t.Errorf("Unexpected subtask tree %s", result)
+	}
+	if status := tasks[0].SubTasks[0].Children[0].Status; status != model.StatusDone {
+		t.Errorf("Expected nested subtask to be done, got %v", status)
+	}
+	if line := tasks[0].SubTasks[0].Children[0].Children[0].Position.StartLine; line != 5 {
+		t.Errorf("Expected nested subtask on line 5, got %d", line)
+	}
+}
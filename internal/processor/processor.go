@@ -1,8 +1,15 @@
 package processor
 
-import "github.com/ahmaruff/tada/internal/model"
+import (
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+)
 
 func ConsolidateTasks(sections []model.Section) []model.Section {
+	// Entries dated before a task was last reopened do not decide its status
+	reopened := reopenDays(sections)
+
 	// Build a map of task updates from Todo, Done, and Archives sections
 	taskUpdates := make(map[string]*model.Task)
 
@@ -11,6 +18,12 @@ func ConsolidateTasks(sections []model.Section) []model.Section {
 		if section.Name == model.SectionTodo || section.Name == model.SectionDone || section.Name == model.SectionArchives {
 			for _, task := range section.Tasks {
 
+				if day, ok := reopened[task.ID]; ok && datedBefore(task, day) {
+					// Entries before the reopen only add descriptions and subtasks
+					task.Status = model.StatusTodo
+					task.EndDate = nil
+				}
+
 				if task.ID != "" {
 					// If we already have an update for this ID, merge the information
 					if existingUpdate, exists := taskUpdates[task.ID]; exists {
@@ -30,16 +43,18 @@ func ConsolidateTasks(sections []model.Section) []model.Section {
 	updatedSections := make([]model.Section, len(sections))
 
 	for i, section := range sections {
-		updatedSections[i] = model.Section{
-			Name:  section.Name,
-			Tasks: make([]model.Task, len(section.Tasks)),
-		}
+		updatedSections[i] = section
+		updatedSections[i].Tasks = make([]model.Task, len(section.Tasks))
 
 		for j, task := range section.Tasks {
 			if section.Name == model.SectionBacklog && task.ID != "" {
 				// Update Backlog task if we have update data
 				if updateData, exists := taskUpdates[task.ID]; exists {
-					updatedSections[i].Tasks[j] = applyTaskUpdate(task, updateData)
+					updated := applyTaskUpdate(task, updateData)
+					if _, ok := reopened[task.ID]; ok && updated.Status == model.StatusTodo {
+						updated.EndDate = nil
+					}
+					updatedSections[i].Tasks[j] = updated
 				} else {
 					// No update data, keep original
 					updatedSections[i].Tasks[j] = task
@@ -54,6 +69,60 @@ func ConsolidateTasks(sections []model.Section) []model.Section {
 	return updatedSections
 }
 
+// lastDoneDays returns the day each task was last completed, according to
+// its done entries in Todo, Done and Archives.
+func lastDoneDays(sections []model.Section) map[string]time.Time {
+	days := make(map[string]time.Time)
+	for _, section := range sections {
+		if !section.Name.HasDateGroups() && section.Name != model.SectionArchives {
+			continue
+		}
+		for _, task := range section.Tasks {
+			if task.ID == "" || task.Status != model.StatusDone {
+				continue
+			}
+			day := task.EndDate
+			if day == nil {
+				day = task.StartDate
+			}
+			if day != nil && day.After(days[task.ID]) {
+				days[task.ID] = *day
+			}
+		}
+	}
+	return days
+}
+
+// reopenDays returns the day each task was last reopened, according to the
+// entries marked as reopened in Todo and Done.
+func reopenDays(sections []model.Section) map[string]time.Time {
+	days := make(map[string]time.Time)
+	for _, section := range sections {
+		if !section.Name.HasDateGroups() {
+			continue
+		}
+		for _, task := range section.Tasks {
+			if task.ID == "" || !task.Reopened || task.StartDate == nil {
+				continue
+			}
+			if day, ok := days[task.ID]; !ok || task.StartDate.After(day) {
+				days[task.ID] = *task.StartDate
+			}
+		}
+	}
+	return days
+}
+
+// datedBefore reports whether an entry ends before day. Undated entries
+// count as earlier.
+func datedBefore(task model.Task, day time.Time) bool {
+	date := task.EndDate
+	if date == nil {
+		date = task.StartDate
+	}
+	return date == nil || date.Before(day)
+}
+
 // applyTaskUpdate applies update data to a Backlog task
 func applyTaskUpdate(original model.Task, update *model.Task) model.Task {
 	updated := original
@@ -136,48 +205,52 @@ func mergeDescriptions(existing []string, new []string) []string {
 	return result
 }
 
-// mergeSubtasks combines subtask slices, avoiding duplicates by content
+// mergeSubtasks combines subtask trees, matching subtasks by their path of
+// contents: siblings with the same content are merged, in order when the
+// content repeats, and their children merged in turn. Existing subtasks are
+// kept in their order and new ones are appended in the order they are first
+// seen, so consolidating twice gives the same result.
 func mergeSubtasks(existing []model.Subtask, new []model.Subtask) []model.Subtask {
 	if len(new) == 0 {
 		return existing
 	}
-	if len(existing) == 0 {
-		return new
-	}
 
-	// Create a map to track subtasks by content
-	subtaskMap := make(map[string]model.Subtask)
+	// Track the positions of the subtasks in result by content
+	result := make([]model.Subtask, 0, len(existing)+len(new))
+	index := make(map[string][]int)
 
-	// Add existing subtasks to map
 	for _, subtask := range existing {
-		if subtask.Content != "" {
-			subtaskMap[subtask.Content] = subtask
+		if subtask.Content == "" {
+			continue
 		}
+		index[subtask.Content] = append(index[subtask.Content], len(result))
+		result = append(result, subtask)
 	}
 
 	// Add or update subtasks from new data
+	seen := make(map[string]int)
 	for _, newSubtask := range new {
-		if newSubtask.Content != "" {
-			if existingSubtask, exists := subtaskMap[newSubtask.Content]; exists {
-				// Update existing subtask status if new one is "higher priority"
-				if newSubtask.Status == model.StatusDone {
-					existingSubtask.Status = model.StatusDone
-				} else if newSubtask.Status == model.StatusInProgress && existingSubtask.Status != model.StatusDone {
-					existingSubtask.Status = model.StatusInProgress
-				}
-				// Update the map with the modified subtask
-				subtaskMap[newSubtask.Content] = existingSubtask
-			} else {
-				// Add new subtask
-				subtaskMap[newSubtask.Content] = newSubtask
-			}
+		if newSubtask.Content == "" {
+			continue
 		}
-	}
+		occurrence := seen[newSubtask.Content]
+		seen[newSubtask.Content]++
+
+		positions := index[newSubtask.Content]
+		if occurrence >= len(positions) {
+			index[newSubtask.Content] = append(positions, len(result))
+			result = append(result, newSubtask)
+			continue
+		}
+		i := positions[occurrence]
 
-	// Convert map back to slice
-	result := make([]model.Subtask, 0, len(subtaskMap))
-	for _, subtask := range subtaskMap {
-		result = append(result, subtask)
+		// Update existing subtask status if new one is "higher priority"
+		if newSubtask.Status == model.StatusDone {
+			result[i].Status = model.StatusDone
+		} else if newSubtask.Status == model.StatusInProgress && result[i].Status != model.StatusDone {
+			result[i].Status = model.StatusInProgress
+		}
+		result[i].Children = mergeSubtasks(result[i].Children, newSubtask.Children)
 	}
 
 	return result
@@ -0,0 +1,93 @@
+package processor
+
+import (
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+)
+
+// TaskIDs returns every task ID in use across all sections.
+func TaskIDs(sections []model.Section) map[string]bool {
+	ids := make(map[string]bool)
+	for _, section := range sections {
+		for _, task := range section.Tasks {
+			if task.ID != "" {
+				ids[task.ID] = true
+			}
+		}
+	}
+	return ids
+}
+
+// NextTaskID returns a numeric ID one higher than the highest numeric ID in
+// use, skipping any ID that is already taken.
+func NextTaskID(sections []model.Section) string {
+	return nextSequentialID(TaskIDs(sections))
+}
+
+// AddTask appends a task to Backlog. If todoDate is set, a copy of the task
+// is also added to Todo under that date. Missing sections are created.
+func AddTask(sections []model.Section, task model.Task, todoDate *time.Time) []model.Section {
+	result, backlog := ensureSection(sections, model.SectionBacklog, 0)
+	result[backlog].Tasks = append(result[backlog].Tasks, task)
+
+	if todoDate != nil {
+		var todo int
+		result, todo = ensureSection(result, model.SectionTodo, backlog+1)
+
+		todoTask := task
+		todoTask.Description = []string{}
+		todoTask.SubTasks = []model.Subtask{}
+		todoTask.StartDate = todoDate
+		todoTask.EndDate = todoDate
+
+		result[todo].Tasks = insertDatedTask(result[todo].Tasks, todoTask)
+	}
+
+	return result
+}
+
+// ensureSection returns a copy of sections containing the named section,
+// inserting an empty one at index at if needed, and the section's index.
+func ensureSection(sections []model.Section, name model.SectionName, at int) ([]model.Section, int) {
+	result := make([]model.Section, 0, len(sections)+1)
+	result = append(result, sections...)
+
+	for i, section := range result {
+		if section.Name == name {
+			// Copy tasks so the caller's slice is not modified
+			result[i].Tasks = append([]model.Task{}, section.Tasks...)
+			return result, i
+		}
+	}
+
+	if at > len(result) {
+		at = len(result)
+	}
+	result = append(result[:at], append([]model.Section{{Name: name}}, result[at:]...)...)
+	return result, at
+}
+
+// insertDatedTask adds a task after the last task with the same start date,
+// or at the top when there is none, so the newest date group comes first.
+func insertDatedTask(tasks []model.Task, task model.Task) []model.Task {
+	at := 0
+	for i, existing := range tasks {
+		if sameDay(existing.StartDate, task.StartDate) {
+			at = i + 1
+		}
+	}
+
+	result := make([]model.Task, 0, len(tasks)+1)
+	result = append(result, tasks[:at]...)
+	result = append(result, task)
+	result = append(result, tasks[at:]...)
+	return result
+}
+
+func sameDay(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
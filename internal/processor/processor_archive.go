@@ -12,10 +12,8 @@ func MoveCompletedBacklogToArchives(sections []model.Section) []model.Section {
 	var completedTasks []model.Task
 
 	for i, section := range sections {
-		result[i] = model.Section{
-			Name:  section.Name,
-			Tasks: make([]model.Task, 0),
-		}
+		result[i] = section
+		result[i].Tasks = make([]model.Task, 0)
 
 		switch section.Name {
 		case model.SectionBacklog:
@@ -46,9 +44,7 @@ func ClearArchives(sections []model.Section) []model.Section {
 	result := make([]model.Section, len(sections))
 
 	for i, section := range sections {
-		result[i] = model.Section{
-			Name: section.Name,
-		}
+		result[i] = section
 
 		if section.Name == model.SectionArchives {
 			// Clear all tasks from Archives
@@ -63,7 +59,7 @@ func ClearArchives(sections []model.Section) []model.Section {
 }
 
 func sortTasksByDate(tasks []model.Task) {
-	sort.Slice(tasks, func(i, j int) bool {
+	sort.SliceStable(tasks, func(i, j int) bool {
 		// Handle nil dates - put them at the end
 		if tasks[i].StartDate == nil && tasks[j].StartDate == nil {
 			return false // maintain original order for tasks without dates
@@ -0,0 +1,167 @@
+package processor
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+)
+
+// TaskFilter selects tasks. Empty fields match everything; a task must
+// match every field that is set, and any of the values within a field.
+type TaskFilter struct {
+	Sections   []model.SectionName
+	Statuses   []model.TaskStatus
+	Projects   []string
+	IDs        []string
+	From       *time.Time     // task dates overlap From..To
+	To         *time.Time     // task dates overlap From..To
+	Title      string         // case-insensitive substring of the title
+	TitleRegex *regexp.Regexp // regular expression matching the title
+}
+
+// FilterTasks returns the tasks matching the filter, in file order.
+func FilterTasks(sections []model.Section, filter TaskFilter) []model.Task {
+	var result []model.Task
+
+	for _, section := range sections {
+		if len(filter.Sections) > 0 && !slices.Contains(filter.Sections, section.Name) {
+			continue
+		}
+
+		for _, task := range section.Tasks {
+			if filter.Match(task) {
+				result = append(result, task)
+			}
+		}
+	}
+
+	return result
+}
+
+// Match reports whether a task matches every field of the filter except
+// Sections, which only FilterTasks can check.
+func (f TaskFilter) Match(task model.Task) bool {
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, task.Status) {
+		return false
+	}
+
+	if len(f.Projects) > 0 && !ContainsFold(f.Projects, task.Project) {
+		return false
+	}
+
+	if len(f.IDs) > 0 && !slices.Contains(f.IDs, task.ID) {
+		return false
+	}
+
+	if f.Title != "" && !strings.Contains(strings.ToLower(task.Title), strings.ToLower(f.Title)) {
+		return false
+	}
+
+	if f.TitleRegex != nil && !f.TitleRegex.MatchString(task.Title) {
+		return false
+	}
+
+	if f.From != nil || f.To != nil {
+		return overlaps(task, f.From, f.To)
+	}
+
+	return true
+}
+
+// overlaps reports whether the task's dates overlap from..to. Tasks without
+// dates never do; a missing end date means a single day.
+func overlaps(task model.Task, from, to *time.Time) bool {
+	if task.StartDate == nil {
+		return false
+	}
+
+	end := task.StartDate
+	if task.EndDate != nil {
+		end = task.EndDate
+	}
+
+	if from != nil && end.Before(*from) {
+		return false
+	}
+	if to != nil && task.StartDate.After(*to) {
+		return false
+	}
+	return true
+}
+
+// SortTasks sorts tasks in place by the given key: id, title, project,
+// status, start or end. Unknown keys keep the current order.
+func SortTasks(tasks []model.Task, key string) {
+	less := map[string]func(a, b model.Task) bool{
+		"id":      func(a, b model.Task) bool { return compareIDs(a.ID, b.ID) },
+		"title":   func(a, b model.Task) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) },
+		"project": func(a, b model.Task) bool { return strings.ToLower(a.Project) < strings.ToLower(b.Project) },
+		"status":  func(a, b model.Task) bool { return statusRank(a.Status) < statusRank(b.Status) },
+		"start":   func(a, b model.Task) bool { return dateBefore(a.StartDate, b.StartDate) },
+		"end":     func(a, b model.Task) bool { return dateBefore(a.EndDate, b.EndDate) },
+	}[key]
+
+	if less == nil {
+		return
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return less(tasks[i], tasks[j])
+	})
+}
+
+// compareIDs orders numeric IDs by value and other IDs alphabetically.
+func compareIDs(a, b string) bool {
+	if len(a) != len(b) && isDigits(a) && isDigits(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func statusRank(status model.TaskStatus) int {
+	switch status {
+	case model.StatusInProgress:
+		return 0
+	case model.StatusTodo:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// dateBefore orders dates ascending with missing dates last.
+func dateBefore(a, b *time.Time) bool {
+	if a == nil {
+		return false
+	}
+	if b == nil {
+		return true
+	}
+	return a.Before(*b)
+}
+
+// ContainsFold reports whether values contains value, ignoring case.
+func ContainsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,286 @@
+package processor
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+)
+
+// IDStyle selects how AssignIDs generates task IDs.
+type IDStyle string
+
+const (
+	IDStyleSequential IDStyle = "sequential" // 1, 2, 3 across all tasks
+	IDStyleProject    IDStyle = "project"    // crm-1, crm-2, hrm-1 per project
+	IDStyleHash       IDStyle = "hash"       // short hash of project and title
+	IDStyleULID       IDStyle = "ulid"       // time-ordered unique ID
+)
+
+// ParseIDStyle checks an ID style name.
+func ParseIDStyle(value string) (IDStyle, error) {
+	switch style := IDStyle(strings.ToLower(value)); style {
+	case IDStyleSequential, IDStyleProject, IDStyleHash, IDStyleULID:
+		return style, nil
+	case "":
+		return IDStyleSequential, nil
+	default:
+		return "", fmt.Errorf("unknown ID style %q, expected sequential, project, hash or ulid", value)
+	}
+}
+
+// Sources of time and randomness for ULIDs, replaced in tests.
+var (
+	ulidNow  = time.Now
+	ulidRead = rand.Read
+)
+
+// AssignIDs gives every Backlog task without an ID a new unique ID and
+// returns the updated sections with the number of IDs assigned.
+func AssignIDs(sections []model.Section, style IDStyle) ([]model.Section, int) {
+	ids := TaskIDs(sections)
+	result := make([]model.Section, len(sections))
+	assigned := 0
+
+	for i, section := range sections {
+		result[i] = section
+		if section.Name != model.SectionBacklog {
+			continue
+		}
+
+		result[i].Tasks = make([]model.Task, len(section.Tasks))
+		for j, task := range section.Tasks {
+			if task.ID == "" {
+				task.ID = newTaskID(task, style, ids)
+				ids[task.ID] = true
+				assigned++
+			}
+			result[i].Tasks[j] = task
+		}
+	}
+
+	return result, assigned
+}
+
+func newTaskID(task model.Task, style IDStyle, ids map[string]bool) string {
+	switch style {
+	case IDStyleProject:
+		return nextProjectID(task.Project, ids)
+	case IDStyleHash:
+		return hashID(task, ids)
+	case IDStyleULID:
+		return ulidID(ids)
+	default:
+		return nextSequentialID(ids)
+	}
+}
+
+func nextSequentialID(ids map[string]bool) string {
+	next := 1
+	for id := range ids {
+		if n, err := strconv.Atoi(id); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+
+	for ids[strconv.Itoa(next)] {
+		next++
+	}
+	return strconv.Itoa(next)
+}
+
+func nextProjectID(project string, ids map[string]bool) string {
+	prefix := strings.ToLower(strings.Join(strings.Fields(project), "-"))
+	if prefix == "" {
+		prefix = "task"
+	}
+
+	pattern := regexp.MustCompile(`^` + regexp.QuoteMeta(prefix) + `-(\d+)$`)
+	next := 1
+	for id := range ids {
+		if matches := pattern.FindStringSubmatch(id); matches != nil {
+			if n, err := strconv.Atoi(matches[1]); err == nil && n >= next {
+				next = n + 1
+			}
+		}
+	}
+
+	for ids[fmt.Sprintf("%s-%d", prefix, next)] {
+		next++
+	}
+	return fmt.Sprintf("%s-%d", prefix, next)
+}
+
+// hashID returns the shortest prefix (at least 7 characters) of the task's
+// hash that is not in use yet.
+func hashID(task model.Task, ids map[string]bool) string {
+	sum := sha1.Sum([]byte(task.Project + "\x00" + task.Title))
+	hash := hex.EncodeToString(sum[:])
+
+	for length := 7; length <= len(hash); length++ {
+		if !ids[hash[:length]] {
+			return hash[:length]
+		}
+	}
+
+	for n := 2; ; n++ {
+		if id := fmt.Sprintf("%s-%d", hash[:7], n); !ids[id] {
+			return id
+		}
+	}
+}
+
+func ulidID(ids map[string]bool) string {
+	for {
+		var b [16]byte
+		binary.BigEndian.PutUint64(b[:8], uint64(ulidNow().UnixMilli())<<16)
+		if _, err := ulidRead(b[6:]); err != nil {
+			panic(fmt.Sprintf("failed to read random bytes: %v", err))
+		}
+
+		if id := encodeULID(b); !ids[id] {
+			return id
+		}
+	}
+}
+
+// encodeULID writes 128 bits as 26 Crockford base32 characters.
+func encodeULID(b [16]byte) string {
+	const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	var out [26]byte
+	for i := range out {
+		var v byte
+		for j := 0; j < 5; j++ {
+			// 130 output bits, the first two are padding
+			bit := i*5 + j - 2
+			v <<= 1
+			if bit >= 0 && b[bit/8]&(0x80>>(bit%8)) != 0 {
+				v |= 1
+			}
+		}
+		out[i] = alphabet[v]
+	}
+	return string(out[:])
+}
+
+// TitleMatch proposes linking an ID-less Todo/Done task to a Backlog task
+// with a similar title.
+type TitleMatch struct {
+	Section   model.SectionName
+	Index     int        // index of the task in its section
+	Task      model.Task // the ID-less task
+	Candidate model.Task // the Backlog task it probably refers to
+	Score     float64    // title similarity between 0 and 1
+}
+
+// FindTitleMatches returns, for every Todo/Done task without an ID, the
+// Backlog task with the most similar title scoring at least threshold.
+func FindTitleMatches(sections []model.Section, threshold float64) []TitleMatch {
+	var backlog []model.Task
+	for _, section := range sections {
+		if section.Name == model.SectionBacklog {
+			for _, task := range section.Tasks {
+				if task.ID != "" {
+					backlog = append(backlog, task)
+				}
+			}
+		}
+	}
+
+	var matches []TitleMatch
+	for _, section := range sections {
+		if section.Name != model.SectionTodo && section.Name != model.SectionDone {
+			continue
+		}
+
+		for i, task := range section.Tasks {
+			if task.ID != "" {
+				continue
+			}
+
+			best := TitleMatch{Score: -1}
+			for _, candidate := range backlog {
+				if task.Project != "" && candidate.Project != "" && task.Project != candidate.Project {
+					continue
+				}
+				if score := titleSimilarity(task.Title, candidate.Title); score > best.Score {
+					best = TitleMatch{Section: section.Name, Index: i, Task: task, Candidate: candidate, Score: score}
+				}
+			}
+
+			if best.Score >= threshold {
+				matches = append(matches, best)
+			}
+		}
+	}
+
+	return matches
+}
+
+// LinkTask gives the matched Todo/Done task the ID (and project, if it has
+// none) of its Backlog candidate.
+func LinkTask(sections []model.Section, match TitleMatch) []model.Section {
+	result := make([]model.Section, len(sections))
+	copy(result, sections)
+
+	for i, section := range result {
+		if section.Name != match.Section || match.Index >= len(section.Tasks) {
+			continue
+		}
+
+		result[i].Tasks = append([]model.Task{}, section.Tasks...)
+		task := &result[i].Tasks[match.Index]
+		task.ID = match.Candidate.ID
+		if task.Project == "" {
+			task.Project = match.Candidate.Project
+		}
+		break
+	}
+
+	return result
+}
+
+// titleSimilarity compares titles ignoring case and spacing, returning 1
+// for equal titles and 0 for completely different ones.
+func titleSimilarity(a, b string) float64 {
+	a = strings.ToLower(strings.Join(strings.Fields(a), " "))
+	b = strings.ToLower(strings.Join(strings.Fields(b), " "))
+
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
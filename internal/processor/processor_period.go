@@ -0,0 +1,54 @@
+package processor
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+)
+
+// CollectPeriodTasks returns the tasks whose dates overlap from..to, newest
+// first. Tasks come from Archives and Backlog, then from stored (the archive
+// store), then from Done for entries that are in neither, so each task is
+// reported once. Sections should be consolidated first.
+func CollectPeriodTasks(sections []model.Section, stored []model.Task, from, to time.Time) []model.Task {
+	var sources [][]model.Task
+	for _, name := range []model.SectionName{model.SectionArchives, model.SectionBacklog} {
+		for _, section := range sections {
+			if section.Name == name {
+				sources = append(sources, section.Tasks)
+			}
+		}
+	}
+	sources = append(sources, stored)
+	for _, section := range sections {
+		if section.Name == model.SectionDone {
+			sources = append(sources, section.Tasks)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var result []model.Task
+	for _, tasks := range sources {
+		for _, task := range tasks {
+			key := TaskKey(task)
+			if seen[key] || !overlaps(task, &from, &to) {
+				continue
+			}
+			seen[key] = true
+			result = append(result, task)
+		}
+	}
+
+	sortTasksByDate(result)
+	return result
+}
+
+// TaskKey identifies a task across sections: by ID, or by project and title
+// for tasks without one.
+func TaskKey(task model.Task) string {
+	if task.ID != "" {
+		return "#" + task.ID
+	}
+	return strings.ToLower(task.Project) + "|" + strings.ToLower(strings.Join(strings.Fields(task.Title), " "))
+}
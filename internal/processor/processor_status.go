@@ -0,0 +1,169 @@
+package processor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+)
+
+// StartTask marks a Backlog task as in progress and records it under the
+// given day in Todo.
+func StartTask(sections []model.Section, id string, day time.Time) ([]model.Section, error) {
+	task, err := findBacklogTask(sections, id)
+	if err != nil {
+		return sections, err
+	}
+	if task.Status == model.StatusDone {
+		return sections, fmt.Errorf("task #%s is already done, reopen it first", id)
+	}
+
+	result := updateBacklogTask(sections, id, func(task *model.Task) {
+		task.Status = model.StatusInProgress
+		if task.StartDate == nil {
+			task.StartDate = &day
+		}
+	})
+
+	return recordDayEntry(result, task, model.SectionTodo, model.StatusInProgress, day), nil
+}
+
+// CompleteTask marks a Backlog task as done and records it under the given
+// day in Done.
+func CompleteTask(sections []model.Section, id string, day time.Time) ([]model.Section, error) {
+	task, err := findBacklogTask(sections, id)
+	if err != nil {
+		return sections, err
+	}
+
+	result := updateBacklogTask(sections, id, func(task *model.Task) {
+		task.Status = model.StatusDone
+		if task.StartDate == nil {
+			task.StartDate = &day
+		}
+		task.EndDate = &day
+	})
+
+	return recordDayEntry(result, task, model.SectionDone, model.StatusDone, day), nil
+}
+
+// ReopenTask marks a done Backlog task as todo again and records a todo
+// entry marked as reopened under the given day in Todo. Consolidation then
+// ignores the status of entries dated before that day. A completion on the
+// same day is taken back instead: its entry in Done is removed. Earlier
+// entries in Todo and Done are left as they are.
+func ReopenTask(sections []model.Section, id string, day time.Time) ([]model.Section, error) {
+	task, err := findBacklogTask(sections, id)
+	if err != nil {
+		return sections, err
+	}
+	if task.Status != model.StatusDone {
+		return sections, fmt.Errorf("task #%s is not done", id)
+	}
+	if last, ok := lastDoneDays(sections)[id]; ok && day.Before(last) {
+		return sections, fmt.Errorf("task #%s was completed on %s, reopen it on that day or later",
+			id, last.Format("2006-01-02"))
+	}
+
+	result := updateBacklogTask(sections, id, func(task *model.Task) {
+		task.Status = model.StatusTodo
+		task.EndDate = nil
+	})
+
+	for i, section := range result {
+		if section.Name != model.SectionDone {
+			continue
+		}
+
+		result[i].Tasks = make([]model.Task, 0, len(section.Tasks))
+		for _, entry := range section.Tasks {
+			if entry.ID == id && entry.Status == model.StatusDone && !datedBefore(entry, day) {
+				continue
+			}
+			result[i].Tasks = append(result[i].Tasks, entry)
+		}
+	}
+
+	result = recordDayEntry(result, task, model.SectionTodo, model.StatusTodo, day)
+	for i, section := range result {
+		if section.Name != model.SectionTodo {
+			continue
+		}
+		for j, entry := range section.Tasks {
+			if entry.ID == id && sameDay(entry.StartDate, &day) {
+				result[i].Tasks[j].Reopened = true
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func findBacklogTask(sections []model.Section, id string) (model.Task, error) {
+	for _, section := range sections {
+		if section.Name != model.SectionBacklog {
+			continue
+		}
+		for _, task := range section.Tasks {
+			if task.ID == id {
+				return task, nil
+			}
+		}
+	}
+	return model.Task{}, fmt.Errorf("task #%s not found in Backlog", id)
+}
+
+func updateBacklogTask(sections []model.Section, id string, update func(task *model.Task)) []model.Section {
+	result := make([]model.Section, len(sections))
+	copy(result, sections)
+
+	for i, section := range result {
+		if section.Name != model.SectionBacklog {
+			continue
+		}
+
+		result[i].Tasks = append([]model.Task{}, section.Tasks...)
+		for j := range result[i].Tasks {
+			if result[i].Tasks[j].ID == id {
+				update(&result[i].Tasks[j])
+			}
+		}
+	}
+
+	return result
+}
+
+// recordDayEntry sets the status of the task's entry under the given day
+// in a dated section, adding the entry if there is none yet.
+func recordDayEntry(sections []model.Section, task model.Task, name model.SectionName, status model.TaskStatus, day time.Time) []model.Section {
+	// A missing section goes before Archives
+	at := len(sections)
+	for i, section := range sections {
+		if section.Name == model.SectionArchives {
+			at = i
+			break
+		}
+	}
+	result, index := ensureSection(sections, name, at)
+
+	for j, entry := range result[index].Tasks {
+		if entry.ID == task.ID && sameDay(entry.StartDate, &day) {
+			result[index].Tasks[j].Status = status
+			return result
+		}
+	}
+
+	entry := model.Task{
+		ID:          task.ID,
+		Title:       task.Title,
+		Project:     task.Project,
+		Status:      status,
+		StartDate:   &day,
+		EndDate:     &day,
+		Description: []string{},
+		SubTasks:    []model.Subtask{},
+	}
+	result[index].Tasks = insertDatedTask(result[index].Tasks, entry)
+
+	return result
+}
@@ -1,10 +1,18 @@
 package processor
 
 import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ahmaruff/tada/internal/model"
+	"github.com/ahmaruff/tada/internal/parser"
+	"github.com/ahmaruff/tada/internal/writer"
 )
 
 func TestConsolidateTasks(t *testing.T) {
@@ -359,6 +367,628 @@ func TestMergeSubtasks(t *testing.T) {
 	} else if subtask2.Status != model.StatusInProgress {
 		t.Errorf("Expected subtask2 status to remain InProgress, got %v", subtask2.Status)
 	}
+
+	// Existing subtasks keep their order, new ones are appended
+	var order []string
+	for _, subtask := range result {
+		order = append(order, subtask.Content)
+	}
+	if strings.Join(order, ",") != "subtask1,subtask2,subtask3" {
+		t.Errorf("Expected order subtask1,subtask2,subtask3, got %s", strings.Join(order, ","))
+	}
+}
+
+func TestMergeNestedSubtasks(t *testing.T) {
+	existing := []model.Subtask{
+		{Status: model.StatusTodo, Content: "API", Children: []model.Subtask{
+			{Status: model.StatusTodo, Content: "auth", Children: []model.Subtask{
+				{Status: model.StatusTodo, Content: "tokens"},
+			}},
+		}},
+		{Status: model.StatusTodo, Content: "docs"},
+	}
+
+	new := []model.Subtask{
+		{Status: model.StatusTodo, Content: "docs", Children: []model.Subtask{
+			{Status: model.StatusTodo, Content: "tokens"}, // Same content, different path
+		}},
+		{Status: model.StatusTodo, Content: "API", Children: []model.Subtask{
+			{Status: model.StatusInProgress, Content: "auth", Children: []model.Subtask{
+				{Status: model.StatusDone, Content: "tokens"},
+				{Status: model.StatusTodo, Content: "sessions"},
+			}},
+		}},
+	}
+
+	result := mergeSubtasks(existing, new)
+
+	var lines []string
+	model.WalkSubtasks(result, func(subtask *model.Subtask, depth int) {
+		lines = append(lines, fmt.Sprintf("%s%s %s", strings.Repeat(".", depth), subtask.Status, subtask.Content))
+	})
+
+	expected := "[ ] API|.[-] auth|..[x] tokens|..[ ] sessions|[ ] docs|.[ ] tokens"
+	if strings.Join(lines, "|") != expected {
+		t.Errorf("Expected %s, got %s", expected, strings.Join(lines, "|"))
+	}
+
+	// The existing tree is not modified
+	if existing[0].Children[0].Children[0].Status != model.StatusTodo || len(existing[0].Children[0].Children) != 1 {
+		t.Errorf("Expected existing subtasks to be left unchanged, got %+v", existing[0].Children[0])
+	}
+}
+
+// randomTaskFile builds an input file from small pools of IDs, subtasks and
+// descriptions, so that sections often refer to the same tasks.
+func randomTaskFile(r *rand.Rand) string {
+	statuses := []string{" ", "-", "x"}
+	var b strings.Builder
+
+	writeDetails := func() {
+		for i := r.Intn(3); i > 0; i-- {
+			fmt.Fprintf(&b, "  note %d\n", r.Intn(3))
+		}
+		depth := 0
+		for i := r.Intn(5); i > 0; i-- {
+			// Nest under the previous subtask at most one level deeper
+			depth = r.Intn(depth + 2)
+			fmt.Fprintf(&b, "%s- [%s] step %c\n", strings.Repeat("  ", depth+1), statuses[r.Intn(3)], 'a'+r.Intn(4))
+		}
+	}
+
+	b.WriteString("## Backlog\n")
+	for i := r.Intn(6); i > 0; i-- {
+		id := r.Intn(6) + 1
+		fmt.Fprintf(&b, "- [%s] Task %d <!-- @p%d|#%d -->\n", statuses[r.Intn(3)], id, id%2, id)
+		writeDetails()
+	}
+
+	for _, section := range []string{"Todo", "Done"} {
+		fmt.Fprintf(&b, "\n## %s\n", section)
+		for day := 10 + r.Intn(3); day < 16; day += 1 + r.Intn(3) {
+			date := time.Date(2025, 9, day, 0, 0, 0, 0, time.UTC)
+			fmt.Fprintf(&b, "### %s - Day\n", date.Format("2006-01-02"))
+			for i := r.Intn(3) + 1; i > 0; i-- {
+				id := r.Intn(6) + 1
+				fmt.Fprintf(&b, "- [%s] Task %d <!-- #%d -->\n", statuses[r.Intn(3)], id, id)
+				writeDetails()
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// tidyContent parses, consolidates and writes content like tada tidy.
+func tidyContent(t *testing.T, content string) (string, []model.Section) {
+	t.Helper()
+	sections, err := parser.ParseContent(bufio.NewScanner(strings.NewReader(content)))
+	if err != nil {
+		t.Fatalf("ParseContent failed: %v\n%s", err, content)
+	}
+	sections = ConsolidateTasks(sections)
+	return writer.GenerateInputMarkdown(sections), sections
+}
+
+func TestConsolidateTasksIdempotent(t *testing.T) {
+	for seed := int64(1); seed <= 300; seed++ {
+		input := randomTaskFile(rand.New(rand.NewSource(seed)))
+
+		once, _ := tidyContent(t, input)
+		twice, _ := tidyContent(t, once)
+		if once != twice {
+			t.Fatalf("Seed %d: tidy is not idempotent\ninput:\n%s\nonce:\n%s\ntwice:\n%s", seed, input, once, twice)
+		}
+
+		// The same input always gives the same output
+		for i := 0; i < 3; i++ {
+			if again, _ := tidyContent(t, input); again != once {
+				t.Fatalf("Seed %d: tidy is not deterministic\ninput:\n%s\nfirst:\n%s\nthen:\n%s", seed, input, once, again)
+			}
+		}
+	}
+}
+
+func TestConsolidateTasksObsidian(t *testing.T) {
+	parser.SetObsidianTasks(true)
+	defer parser.SetObsidianTasks(false)
+
+	input := `## Backlog
+- [ ] Call client 🛫 2025-01-15 📅 2025-01-20 ⏫ 🆔 42 <!-- @crm -->
+
+## Todo
+### 2025-01-16 - Kamis
+- [x] Call client 🆔 42
+`
+	expected := `## Backlog
+- [x] Call client 🛫 2025-01-15 📅 2025-01-20 ⏫ 🆔 42 <!-- @crm|#42|2025-01-16 -->
+
+## Todo
+### 2025-01-16 - Kamis
+- [x] Call client 🆔 42
+`
+
+	once, _ := tidyContent(t, input)
+	if once != expected {
+		t.Errorf("Expected markers to be kept:\n%s\ngot:\n%s", expected, once)
+	}
+	if twice, _ := tidyContent(t, once); twice != once {
+		t.Errorf("Expected tidy to be idempotent:\n%s\ngot:\n%s", once, twice)
+	}
+}
+
+func TestConsolidateTasksKeepsSubtaskOrder(t *testing.T) {
+	for seed := int64(1); seed <= 300; seed++ {
+		input := randomTaskFile(rand.New(rand.NewSource(seed)))
+		sections, err := parser.ParseContent(bufio.NewScanner(strings.NewReader(input)))
+		if err != nil {
+			t.Fatalf("ParseContent failed: %v", err)
+		}
+		consolidated := ConsolidateTasks(sections)
+
+		for i, task := range sections[0].Tasks {
+			// Backlog subtasks come first, in their order
+			merged := consolidated[0].Tasks[i].SubTasks
+			if len(merged) < len(task.SubTasks) {
+				t.Fatalf("Seed %d: task #%s lost subtasks: %v -> %v", seed, task.ID, task.SubTasks, merged)
+			}
+			for j, subtask := range task.SubTasks {
+				if merged[j].Content != subtask.Content {
+					t.Fatalf("Seed %d: task #%s subtask %d is %q, expected %q", seed, task.ID, j, merged[j].Content, subtask.Content)
+				}
+			}
+		}
+	}
+}
+
+func TestNextTaskID(t *testing.T) {
+	tests := []struct {
+		name     string
+		ids      []string
+		expected string
+	}{
+		{"no tasks", nil, "1"},
+		{"numeric ids", []string{"3", "12", "7"}, "13"},
+		{"non-numeric ids only", []string{"a", "b"}, "1"},
+		{"mixed ids", []string{"a", "2"}, "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tasks []model.Task
+			for _, id := range tt.ids {
+				tasks = append(tasks, model.Task{ID: id})
+			}
+			sections := []model.Section{{Name: model.SectionDone, Tasks: tasks}}
+
+			if id := NextTaskID(sections); id != tt.expected {
+				t.Errorf("Expected next ID '%s', got '%s'", tt.expected, id)
+			}
+		})
+	}
+}
+
+func TestAddTask(t *testing.T) {
+	sections := []model.Section{
+		{
+			Name:  model.SectionBacklog,
+			Tasks: []model.Task{{ID: "1", Title: "Existing"}},
+		},
+		{
+			Name: model.SectionTodo,
+			Tasks: []model.Task{
+				{ID: "1", Title: "Existing", StartDate: timePtr(2025, 9, 13), EndDate: timePtr(2025, 9, 13)},
+			},
+		},
+	}
+
+	task := model.Task{ID: "2", Title: "New", Description: []string{"desc"}}
+
+	// Same date as an existing group: appended to that group
+	result := AddTask(sections, task, timePtr(2025, 9, 13))
+
+	if len(result[0].Tasks) != 2 || result[0].Tasks[1].ID != "2" {
+		t.Fatalf("Expected task to be appended to Backlog, got %+v", result[0].Tasks)
+	}
+	if result[0].Tasks[1].StartDate != nil {
+		t.Errorf("Expected Backlog task to have no start date, got %v", result[0].Tasks[1].StartDate)
+	}
+	if len(result[1].Tasks) != 2 || result[1].Tasks[1].ID != "2" {
+		t.Fatalf("Expected task to be appended to the date group, got %+v", result[1].Tasks)
+	}
+	if len(result[1].Tasks[1].Description) != 0 {
+		t.Errorf("Expected Todo entry to have no description")
+	}
+
+	// New date: goes on top of Todo
+	result = AddTask(sections, task, timePtr(2025, 9, 14))
+	if result[1].Tasks[0].ID != "2" || !timePtrEqual(result[1].Tasks[0].StartDate, timePtr(2025, 9, 14)) {
+		t.Errorf("Expected task for a new date at the top of Todo, got %+v", result[1].Tasks)
+	}
+
+	// Input sections are not modified
+	if len(sections[0].Tasks) != 1 || len(sections[1].Tasks) != 1 {
+		t.Errorf("Expected input sections to be unchanged")
+	}
+
+	// Missing sections are created
+	result = AddTask(nil, task, timePtr(2025, 9, 14))
+	if len(result) != 2 || result[0].Name != model.SectionBacklog || result[1].Name != model.SectionTodo {
+		t.Errorf("Expected Backlog and Todo sections to be created, got %+v", result)
+	}
+}
+
+func TestAssignIDs(t *testing.T) {
+	sections := []model.Section{
+		{
+			Name: model.SectionBacklog,
+			Tasks: []model.Task{
+				{ID: "crm-1", Title: "Has ID", Project: "crm"},
+				{Title: "CRM task", Project: "crm"},
+				{Title: "Other task", Project: "hrm"},
+				{ID: "7", Title: "Numeric ID"},
+			},
+		},
+		{
+			Name:  model.SectionTodo,
+			Tasks: []model.Task{{Title: "Todo without ID"}},
+		},
+	}
+
+	tests := []struct {
+		style    IDStyle
+		expected []string
+	}{
+		{IDStyleSequential, []string{"crm-1", "8", "9", "7"}},
+		{IDStyleProject, []string{"crm-1", "crm-2", "hrm-1", "7"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			result, assigned := AssignIDs(sections, tt.style)
+
+			if assigned != 2 {
+				t.Errorf("Expected 2 IDs assigned, got %d", assigned)
+			}
+			for i, id := range tt.expected {
+				if result[0].Tasks[i].ID != id {
+					t.Errorf("Expected task %d ID '%s', got '%s'", i, id, result[0].Tasks[i].ID)
+				}
+			}
+			if result[1].Tasks[0].ID != "" {
+				t.Errorf("Expected Todo task to keep no ID, got '%s'", result[1].Tasks[0].ID)
+			}
+			if sections[0].Tasks[1].ID != "" {
+				t.Errorf("Expected input sections to be unchanged")
+			}
+		})
+	}
+
+	t.Run("hash", func(t *testing.T) {
+		result, _ := AssignIDs(sections, IDStyleHash)
+		first, second := result[0].Tasks[1].ID, result[0].Tasks[2].ID
+		if len(first) != 7 || len(second) != 7 || first == second {
+			t.Errorf("Expected two distinct 7 character hashes, got '%s' and '%s'", first, second)
+		}
+
+		again, _ := AssignIDs(sections, IDStyleHash)
+		if again[0].Tasks[1].ID != first {
+			t.Errorf("Expected hash IDs to be stable, got '%s' and '%s'", first, again[0].Tasks[1].ID)
+		}
+	})
+
+	t.Run("ulid", func(t *testing.T) {
+		result, _ := AssignIDs(sections, IDStyleULID)
+		if id := result[0].Tasks[1].ID; len(id) != 26 {
+			t.Errorf("Expected a 26 character ULID, got '%s'", id)
+		}
+	})
+}
+
+func TestEncodeULID(t *testing.T) {
+	var b [16]byte
+	if id := encodeULID(b); id != "00000000000000000000000000" {
+		t.Errorf("Expected zero ULID, got '%s'", id)
+	}
+
+	for i := range b {
+		b[i] = 0xff
+	}
+	if id := encodeULID(b); id != "7ZZZZZZZZZZZZZZZZZZZZZZZZZ" {
+		t.Errorf("Expected max ULID, got '%s'", id)
+	}
+}
+
+func TestFindTitleMatches(t *testing.T) {
+	sections := []model.Section{
+		{
+			Name: model.SectionBacklog,
+			Tasks: []model.Task{
+				{ID: "1", Title: "Fix login bug", Project: "backend"},
+				{ID: "2", Title: "Write docs", Project: "docs"},
+			},
+		},
+		{
+			Name: model.SectionDone,
+			Tasks: []model.Task{
+				{Title: "fix  the login bug", Project: "backend"},
+				{Title: "Write docs", Project: "backend"}, // project mismatch
+				{Title: "Something else"},
+				{ID: "2", Title: "Write docs"},
+			},
+		},
+	}
+
+	matches := FindTitleMatches(sections, 0.75)
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d: %+v", len(matches), matches)
+	}
+
+	match := matches[0]
+	if match.Section != model.SectionDone || match.Index != 0 || match.Candidate.ID != "1" {
+		t.Errorf("Unexpected match %+v", match)
+	}
+
+	result := LinkTask(sections, match)
+	if result[1].Tasks[0].ID != "1" {
+		t.Errorf("Expected linked task to get ID '1', got '%s'", result[1].Tasks[0].ID)
+	}
+	if sections[1].Tasks[0].ID != "" {
+		t.Errorf("Expected input sections to be unchanged")
+	}
+}
+
+func TestTaskTransitions(t *testing.T) {
+	sections := []model.Section{
+		{
+			Name:  model.SectionBacklog,
+			Tasks: []model.Task{{ID: "42", Title: "Fix bug", Project: "api", Status: model.StatusTodo}},
+		},
+		{Name: model.SectionTodo},
+		{Name: model.SectionArchives},
+	}
+
+	day1 := *timePtr(2025, 9, 15)
+	day2 := *timePtr(2025, 9, 16)
+
+	// Start: in progress in Backlog and today's Todo entry
+	result, err := StartTask(sections, "42", day1)
+	if err != nil {
+		t.Fatalf("StartTask failed: %v", err)
+	}
+	if task := result[0].Tasks[0]; task.Status != model.StatusInProgress || !timePtrEqual(task.StartDate, &day1) {
+		t.Errorf("Expected started Backlog task, got %+v", task)
+	}
+	if len(result[1].Tasks) != 1 || result[1].Tasks[0].Status != model.StatusInProgress {
+		t.Fatalf("Expected in progress Todo entry, got %+v", result[1].Tasks)
+	}
+
+	// Starting again on the same day refreshes the entry
+	result, _ = StartTask(result, "42", day1)
+	if len(result[1].Tasks) != 1 {
+		t.Errorf("Expected Todo entry to be refreshed, got %d entries", len(result[1].Tasks))
+	}
+
+	// Done: Done section is created before Archives
+	result, err = CompleteTask(result, "42", day2)
+	if err != nil {
+		t.Fatalf("CompleteTask failed: %v", err)
+	}
+	if len(result) != 4 || result[2].Name != model.SectionDone || result[3].Name != model.SectionArchives {
+		t.Fatalf("Expected Done section before Archives, got %+v", result)
+	}
+	if task := result[0].Tasks[0]; task.Status != model.StatusDone || !timePtrEqual(task.StartDate, &day1) || !timePtrEqual(task.EndDate, &day2) {
+		t.Errorf("Expected done Backlog task from day 1 to day 2, got %+v", task)
+	}
+	if entry := result[2].Tasks[0]; entry.Status != model.StatusDone || !timePtrEqual(entry.StartDate, &day2) {
+		t.Errorf("Expected done entry on day 2, got %+v", entry)
+	}
+
+	if _, err := StartTask(result, "42", day2); err == nil {
+		t.Errorf("Expected error when starting a done task")
+	}
+
+	// A plain todo entry after the completion does not reopen it
+	stale := append([]model.Section{}, result...)
+	later := timePtr(2025, 9, 20)
+	stale[1].Tasks = append([]model.Task{{ID: "42", Title: "Fix bug", Status: model.StatusTodo, StartDate: later, EndDate: later}}, stale[1].Tasks...)
+	if task := ConsolidateTasks(stale)[0].Tasks[0]; task.Status != model.StatusDone {
+		t.Errorf("Expected task to stay done with a later plain todo entry, got %+v", task)
+	}
+
+	// Reopen on the day it was completed: that completion is taken back
+	sameDay, err := ReopenTask(result, "42", day2)
+	if err != nil {
+		t.Fatalf("ReopenTask failed: %v", err)
+	}
+	sameDay = ConsolidateTasks(sameDay)
+	if task := sameDay[0].Tasks[0]; task.Status != model.StatusTodo || task.EndDate != nil {
+		t.Errorf("Expected task reopened on day 2 to be todo without end date, got %+v", task)
+	}
+	if len(sameDay[2].Tasks) != 0 {
+		t.Errorf("Expected done entry on day 2 to be removed, got %+v", sameDay[2].Tasks)
+	}
+	if entry := sameDay[1].Tasks[0]; entry.Status != model.StatusTodo || !entry.Reopened || !timePtrEqual(entry.StartDate, &day2) {
+		t.Errorf("Expected reopened todo entry on day 2, got %+v", entry)
+	}
+	if len(result[2].Tasks) != 1 {
+		t.Errorf("Expected ReopenTask to leave its input unchanged, got %+v", result[2].Tasks)
+	}
+
+	// Not before it was completed
+	if _, err := ReopenTask(result, "42", day1); err == nil {
+		t.Errorf("Expected error when reopening before the completion")
+	}
+
+	// Reopen later: the reopened entry keeps it open, and the history in
+	// Todo and Done stays
+	day3 := *timePtr(2025, 9, 18)
+	done := append([]model.Task{}, result[2].Tasks...)
+	result, err = ReopenTask(result, "42", day3)
+	if err != nil {
+		t.Fatalf("ReopenTask failed: %v", err)
+	}
+	result = ConsolidateTasks(result)
+	if task := result[0].Tasks[0]; task.Status != model.StatusTodo || task.EndDate != nil || !timePtrEqual(task.StartDate, &day1) {
+		t.Errorf("Expected reopened todo task started on day 1 without end date, got %+v", task)
+	}
+	if !reflect.DeepEqual(result[2].Tasks, done) {
+		t.Errorf("Expected Done section to be unchanged, got %+v", result[2].Tasks)
+	}
+	if entries := result[1].Tasks; len(entries) != 2 || entries[0].Status != model.StatusTodo || !entries[0].Reopened ||
+		!timePtrEqual(entries[0].StartDate, &day3) || entries[1].Status != model.StatusInProgress {
+		t.Errorf("Expected day 1 entry kept and a reopened todo entry on day 3, got %+v", entries)
+	}
+
+	// Consolidating again keeps it open
+	if task := ConsolidateTasks(result)[0].Tasks[0]; task.Status != model.StatusTodo || task.EndDate != nil {
+		t.Errorf("Expected reopened task to stay open, got %+v", task)
+	}
+
+	// Starting it again after the reopen counts
+	result, err = StartTask(result, "42", day3)
+	if err != nil {
+		t.Fatalf("StartTask failed: %v", err)
+	}
+	if task := ConsolidateTasks(result)[0].Tasks[0]; task.Status != model.StatusInProgress || !timePtrEqual(task.EndDate, &day3) {
+		t.Errorf("Expected restarted task in progress until day 3, got %+v", task)
+	}
+
+	if _, err := ReopenTask(result, "42", day3); err == nil {
+		t.Errorf("Expected error when reopening an open task")
+	}
+	if _, err := CompleteTask(result, "missing", day2); err == nil {
+		t.Errorf("Expected error for unknown task")
+	}
+}
+
+func TestFilterTasks(t *testing.T) {
+	sections := []model.Section{
+		{
+			Name: model.SectionBacklog,
+			Tasks: []model.Task{
+				{ID: "1", Title: "Fix login bug", Project: "api", Status: model.StatusInProgress, StartDate: timePtr(2025, 9, 10), EndDate: timePtr(2025, 9, 12)},
+				{ID: "2", Title: "Write docs", Project: "docs", Status: model.StatusTodo},
+				{ID: "10", Title: "Deploy API", Project: "API", Status: model.StatusDone, StartDate: timePtr(2025, 9, 14), EndDate: timePtr(2025, 9, 14)},
+			},
+		},
+		{
+			Name: model.SectionDone,
+			Tasks: []model.Task{
+				{ID: "10", Title: "Deploy API", Project: "api", Status: model.StatusDone, StartDate: timePtr(2025, 9, 14)},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		filter   TaskFilter
+		expected []string
+	}{
+		{"everything", TaskFilter{}, []string{"1", "2", "10", "10"}},
+		{"section", TaskFilter{Sections: []model.SectionName{model.SectionDone}}, []string{"10"}},
+		{"status", TaskFilter{Statuses: []model.TaskStatus{model.StatusTodo, model.StatusInProgress}}, []string{"1", "2"}},
+		{"project ignores case", TaskFilter{Sections: []model.SectionName{model.SectionBacklog}, Projects: []string{"api"}}, []string{"1", "10"}},
+		{"id", TaskFilter{IDs: []string{"2"}}, []string{"2"}},
+		{"title substring", TaskFilter{Title: "API"}, []string{"10", "10"}},
+		{"title regex", TaskFilter{TitleRegex: regexp.MustCompile(`^(Fix|Write)\b`)}, []string{"1", "2"}},
+		{"date overlap", TaskFilter{From: timePtr(2025, 9, 12), To: timePtr(2025, 9, 13)}, []string{"1"}},
+		{"open ended range", TaskFilter{From: timePtr(2025, 9, 13)}, []string{"10", "10"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FilterTasks(sections, tt.filter)
+
+			var ids []string
+			for _, task := range result {
+				ids = append(ids, task.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected tasks %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+}
+
+func TestSortTasks(t *testing.T) {
+	tasks := []model.Task{
+		{ID: "10", Title: "b", Status: model.StatusDone, StartDate: timePtr(2025, 9, 14)},
+		{ID: "9", Title: "C", Status: model.StatusTodo},
+		{ID: "a", Title: "a", Status: model.StatusInProgress, StartDate: timePtr(2025, 9, 10)},
+	}
+
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"id", "9,10,a"},
+		{"title", "a,10,9"},
+		{"status", "a,9,10"},
+		{"start", "a,10,9"},
+		{"unknown", "10,9,a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			sorted := append([]model.Task{}, tasks...)
+			SortTasks(sorted, tt.key)
+
+			var ids []string
+			for _, task := range sorted {
+				ids = append(ids, task.ID)
+			}
+			if strings.Join(ids, ",") != tt.expected {
+				t.Errorf("Expected order %s, got %s", tt.expected, strings.Join(ids, ","))
+			}
+		})
+	}
+}
+
+func TestCollectPeriodTasks(t *testing.T) {
+	sections := []model.Section{
+		{
+			Name: model.SectionBacklog,
+			Tasks: []model.Task{
+				{ID: "1", Title: "Parser", Status: model.StatusDone, StartDate: timePtr(2025, 9, 1), EndDate: timePtr(2025, 9, 10)},
+				{ID: "2", Title: "Writer", Status: model.StatusInProgress, StartDate: timePtr(2025, 9, 12)},
+				{ID: "3", Title: "Later", Status: model.StatusTodo, StartDate: timePtr(2025, 10, 1)},
+				{ID: "4", Title: "Undated", Status: model.StatusTodo},
+			},
+		},
+		{
+			Name: model.SectionDone,
+			Tasks: []model.Task{
+				{ID: "1", Title: "Parser", Status: model.StatusDone, StartDate: timePtr(2025, 9, 10)},
+				{Title: "Call  client", Project: "crm", Status: model.StatusDone, StartDate: timePtr(2025, 9, 9)},
+				{Title: "call client", Project: "CRM", Status: model.StatusDone, StartDate: timePtr(2025, 9, 11)},
+			},
+		},
+		{
+			Name: model.SectionArchives,
+			Tasks: []model.Task{
+				{ID: "5", Title: "Release", Status: model.StatusDone, StartDate: timePtr(2025, 9, 8)},
+			},
+		},
+	}
+
+	stored := []model.Task{
+		{ID: "5", Title: "Release (stored)", Status: model.StatusDone, StartDate: timePtr(2025, 9, 8)},
+		{ID: "6", Title: "Old", Status: model.StatusDone, StartDate: timePtr(2025, 8, 1), EndDate: timePtr(2025, 8, 2)},
+		{ID: "7", Title: "Stored", Status: model.StatusDone, StartDate: timePtr(2025, 9, 5), EndDate: timePtr(2025, 9, 6)},
+	}
+
+	result := CollectPeriodTasks(sections, stored, *timePtr(2025, 9, 5), *timePtr(2025, 9, 12))
+
+	var titles []string
+	for _, task := range result {
+		titles = append(titles, task.Title)
+	}
+
+	expected := "Writer,Call  client,Release,Stored,Parser"
+	if strings.Join(titles, ",") != expected {
+		t.Errorf("Expected %s, got %s", expected, strings.Join(titles, ","))
+	}
 }
 
 // Helper function
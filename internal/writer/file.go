@@ -0,0 +1,201 @@
+package writer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultBackups is the number of backups kept per file when none is
+// configured.
+const DefaultBackups = 10
+
+// BackupDir is where backups are kept, relative to the written file.
+const BackupDir = ".tada/backups"
+
+// backupTimeFormat sorts backups by name from oldest to newest.
+const backupTimeFormat = "20060102-150405.000000"
+
+var keepBackups = DefaultBackups
+
+// SetBackups changes how many backups WriteInputFile keeps per file; 0
+// disables them.
+func SetBackups(keep int) {
+	keepBackups = keep
+}
+
+// Observer is told about every file the writer changed; before is nil when
+// the file did not exist, and backup is the path of the backup holding
+// before, if one was made.
+type Observer func(path string, before, after []byte, backup string) error
+
+var observer Observer
+
+// SetObserver registers a function told about every task file and report
+// the writer changes, e.g. to journal them; nil removes it.
+func SetObserver(o Observer) {
+	observer = o
+}
+
+// WriteFileAtomic replaces path with data without ever leaving a partially
+// written file behind: the data goes to a temporary file in the same
+// directory, which is synced and renamed over path. An existing file keeps
+// its mode, and a symlink is followed so that its target is replaced rather
+// than the link.
+func WriteFileAtomic(path string, data []byte) error {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	// Clean up on any failure before the rename
+	fail := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		return fail(err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		return fail(err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fail(err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	// Persist the rename itself; not every platform can sync a directory
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// backupFile copies the current content of path to
+// .tada/backups/<name>.<timestamp> next to it, removes the oldest backups
+// beyond the configured count and returns the path of the new backup. A
+// missing file needs no backup and gives "".
+func backupFile(path string, now time.Time) (string, error) {
+	if keepBackups <= 0 {
+		return "", nil
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(filepath.Dir(path), BackupDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	backup := filepath.Join(dir, filepath.Base(path)+"."+now.Format(backupTimeFormat))
+	if err := os.WriteFile(backup, content, info.Mode().Perm()); err != nil {
+		return "", err
+	}
+
+	backups, err := ListBackups(path)
+	if err != nil {
+		return "", err
+	}
+	for len(backups) > keepBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return "", err
+		}
+		backups = backups[1:]
+	}
+	return backup, nil
+}
+
+// ListBackups returns the backups of path, oldest first.
+func ListBackups(path string) ([]string, error) {
+	dir := filepath.Join(filepath.Dir(path), BackupDir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := filepath.Base(path) + "."
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if _, err := time.Parse(backupTimeFormat, strings.TrimPrefix(name, prefix)); err != nil {
+			continue
+		}
+		backups = append(backups, filepath.Join(dir, name))
+	}
+
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// writeFile replaces path atomically, after backing up its previous
+// version if backup is set, and tells the observer. Unchanged content is not
+// written again, so it does not push out older backups.
+func writeFile(path string, data []byte, backup bool) error {
+	before, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil && bytes.Equal(before, data) {
+		return nil
+	}
+
+	var backupPath string
+	if backup {
+		if backupPath, err = backupFile(path, time.Now()); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	}
+
+	if err := WriteFileAtomic(path, data); err != nil {
+		return err
+	}
+
+	if observer != nil {
+		if err := observer(path, before, data, backupPath); err != nil {
+			return fmt.Errorf("%s was written but not recorded: %w", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "input.md")
+
+	if err := WriteFileAtomic(path, []byte("first\n")); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0644 {
+		t.Errorf("Expected new file mode 0644, got %v", info.Mode().Perm())
+	}
+
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileAtomic(path, []byte("second\n")); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(path)
+	if string(content) != "second\n" {
+		t.Errorf("Expected 'second', got %q", content)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600 to be kept, got %v", info.Mode().Perm())
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected no temporary files left, got %d entries", len(entries))
+	}
+}
+
+func TestWriteFileAtomicSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "vault", "input.md")
+	link := filepath.Join(dir, "input.md")
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("first\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("vault", "input.md"), link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	if err := WriteFileAtomic(link, []byte("second\n")); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("Expected %s to stay a symlink", link)
+	}
+	content, _ := os.ReadFile(target)
+	if string(content) != "second\n" {
+		t.Errorf("Expected target to contain 'second', got %q", content)
+	}
+}
+
+func TestBackupFile(t *testing.T) {
+	defer SetBackups(DefaultBackups)
+	SetBackups(2)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "input.md")
+	now := time.Date(2025, 9, 14, 10, 0, 0, 0, time.UTC)
+
+	// Nothing to back up yet
+	if backup, err := backupFile(path, now); err != nil || backup != "" {
+		t.Fatalf("Expected no backup of a missing file, got %q, %v", backup, err)
+	}
+
+	for i, content := range []string{"v1", "v2", "v3"} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		backup, err := backupFile(path, now.Add(time.Duration(i)*time.Second))
+		if err != nil {
+			t.Fatalf("backupFile failed: %v", err)
+		}
+		if saved, _ := os.ReadFile(backup); string(saved) != content {
+			t.Errorf("Expected backup %q to hold %q, got %q", backup, content, saved)
+		}
+	}
+	os.WriteFile(filepath.Join(dir, BackupDir, "input.md.notes"), []byte("keep"), 0644)
+
+	backups, err := ListBackups(path)
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("Expected 2 backups, got %v", backups)
+	}
+	if !strings.HasSuffix(backups[1], "input.md.20250914-100002.000000") {
+		t.Errorf("Expected newest backup last, got %v", backups)
+	}
+	if content, _ := os.ReadFile(backups[0]); string(content) != "v2" {
+		t.Errorf("Expected oldest kept backup to be v2, got %q", content)
+	}
+
+	SetBackups(0)
+	if backup, err := backupFile(path, now.Add(time.Hour)); err != nil || backup != "" {
+		t.Fatalf("Expected no backup when disabled, got %q, %v", backup, err)
+	}
+	if backups, _ := ListBackups(path); len(backups) != 2 {
+		t.Errorf("Expected no backup when disabled, got %v", backups)
+	}
+}
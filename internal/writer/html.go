@@ -0,0 +1,115 @@
+package writer
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+)
+
+// DefaultHTMLTemplate renders a self-contained HTML page: inline CSS, no
+// scripts or external assets, so it can be mailed as is. Tasks are listed
+// under their group, with a status badge and date range, and descriptions
+// and subtasks fold away in a <details> element.
+const DefaultHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{template "period" .}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; max-width: 760px; margin: 2em auto; padding: 0 1em; line-height: 1.5; }
+h1 { font-size: 1.5em; margin-bottom: 0.2em; }
+.period { color: #59636e; margin-top: 0; }
+h2 { font-size: 1.15em; border-bottom: 1px solid #d1d9e0; padding-bottom: 0.3em; margin-top: 1.8em; }
+.count { color: #59636e; font-weight: normal; }
+ul.tasks { list-style: none; padding: 0; }
+li.task { margin: 0.8em 0; }
+.title { font-weight: 600; }
+.project { color: #59636e; font-size: 0.85em; text-transform: uppercase; margin-right: 0.3em; }
+.dates { color: #59636e; font-size: 0.85em; margin-left: 0.5em; white-space: nowrap; }
+.badge { display: inline-block; font-size: 0.75em; font-weight: 600; padding: 0.1em 0.6em; border-radius: 1em; margin-right: 0.5em; vertical-align: 0.1em; }
+.badge.done { background: #dafbe1; color: #116329; }
+.badge.in-progress { background: #fff8c5; color: #7d4e00; }
+.badge.todo { background: #eff2f5; color: #59636e; }
+details { margin: 0.3em 0 0 0.2em; }
+summary { cursor: pointer; color: #0969da; font-size: 0.9em; }
+.description p { margin: 0.3em 0; }
+ul.subtasks { padding-left: 1.2em; margin: 0.3em 0; }
+ul.subtasks li.done { color: #59636e; text-decoration: line-through; }
+</style>
+</head>
+<body>
+<h1>Report</h1>
+<p class="period">{{template "period" .}}</p>
+{{- if .Groups}}
+{{- range .Groups}}
+<h2>{{.Label}} <span class="count">({{len .Tasks}})</span></h2>
+{{template "tasks" .Tasks}}
+{{- end}}
+{{- else}}
+{{template "tasks" .Tasks}}
+{{- end}}
+</body>
+</html>
+{{define "period"}}{{if .Start}}{{dateRange .Start .End}}{{else}}Report{{end}}{{end}}
+{{- define "tasks"}}<ul class="tasks">
+{{- range .}}
+<li class="task">
+<span class="badge {{status .Status}}">{{status .Status}}</span>
+{{- if .Project}}<span class="project">{{.Project}}</span>{{end}}
+<span class="title">{{.Title}}</span>
+{{- with dateRange .StartDate .EndDate}}<span class="dates">{{.}}</span>{{end}}
+{{- if or .Description .SubTasks}}
+<details>
+<summary>{{if .Description}}Details{{else}}Subtasks{{end}}</summary>
+{{- if .Description}}
+<div class="description">
+{{- range .Description}}
+<p>{{.}}</p>
+{{- end}}
+</div>
+{{- end}}
+{{- if .SubTasks}}
+{{template "subtasks" .SubTasks}}
+{{- end}}
+</details>
+{{- end}}
+</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- define "subtasks"}}<ul class="subtasks">
+{{- range .}}
+<li class="{{status .Status}}">{{.Content}}
+{{- if .Children}}
+{{template "subtasks" .Children}}
+{{- end}}</li>
+{{- end}}
+</ul>
+{{- end}}`
+
+// ParseHTMLTemplate parses an HTML report template with the helpers in
+// TemplateFuncs. Values are escaped for their HTML context.
+func ParseHTMLTemplate(name, text string) (*htmltemplate.Template, error) {
+	return htmltemplate.New(name).Funcs(htmltemplate.FuncMap(TemplateFuncs())).Parse(text)
+}
+
+// LoadHTMLTemplate reads and parses an HTML report template file. An empty
+// path returns the built-in DefaultHTMLTemplate.
+func LoadHTMLTemplate(path string) (*htmltemplate.Template, error) {
+	if path == "" {
+		return ParseHTMLTemplate("default.html", DefaultHTMLTemplate)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", path, err)
+	}
+
+	tmpl, err := ParseHTMLTemplate(filepath.Base(path), string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,68 @@
+package writer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Locale names the days of the week written after the date in generated
+// date headers, e.g. "### 2025-01-16 - Thursday".
+type Locale struct {
+	Name string
+	Days [7]string // indexed by time.Weekday, starting on Sunday
+}
+
+var builtinLocales = map[string]Locale{
+	"en": {
+		Name: "en",
+		Days: [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	},
+	"id": {
+		Name: "id",
+		Days: [7]string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jum'at", "Sabtu"},
+	},
+}
+
+// DefaultLocale is used when no locale is configured.
+const DefaultLocale = "id"
+
+var currentLocale = builtinLocales[DefaultLocale]
+
+// ParseLocale returns a built-in locale by name ("en", "id") or builds a
+// custom one from seven comma-separated day names starting on Sunday.
+func ParseLocale(value string) (Locale, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		value = DefaultLocale
+	}
+
+	if locale, ok := builtinLocales[strings.ToLower(value)]; ok {
+		return locale, nil
+	}
+
+	names := strings.Split(value, ",")
+	if len(names) != 7 {
+		return Locale{}, fmt.Errorf("unknown locale %q: use en, id or 7 comma-separated day names starting on Sunday", value)
+	}
+
+	locale := Locale{Name: "custom"}
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return Locale{}, fmt.Errorf("invalid locale %q: day name %d is empty", value, i+1)
+		}
+		locale.Days[i] = name
+	}
+
+	return locale, nil
+}
+
+// SetLocale changes the day names used for generated date headers.
+func SetLocale(locale Locale) {
+	currentLocale = locale
+}
+
+func getDayName(date time.Time) string {
+	return currentLocale.Days[date.Weekday()]
+}
@@ -0,0 +1,321 @@
+package writer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+)
+
+// DefaultTemplate renders each archived task as a "# PROJECT - Title"
+// heading followed by its dates, description and subtasks. When the report
+// is grouped, each group gets a "# Label (count)" heading and its tasks move
+// down to "##".
+const DefaultTemplate = `{{- define "task"}}{{.Title}}
+{{if and .StartDate .EndDate}}{{dateRange .StartDate .EndDate}}  
+{{end}}{{if .Description}}Desc:  
+{{range .Description}}  {{.}}  
+{{end}}{{end}}{{range subtasks .SubTasks}}  {{repeat .Depth "  "}}- [{{glyph .Status}}] {{.Content}}
+{{end}}{{end}}
+{{- if .GroupBy}}
+{{- range $g, $group := .Groups}}{{if $g}}
+{{end}}# {{.Label}} ({{len .Tasks}})
+{{range .Tasks}}
+## {{if and .Project (ne $.GroupBy "project")}}{{upper .Project}} - {{end}}{{template "task" .}}{{end}}{{end}}
+{{- else}}
+{{- range $i, $task := .Tasks}}{{if $i}}
+{{end}}# {{if .Project}}{{upper .Project}} - {{end}}{{template "task" .}}{{end}}
+{{- end}}`
+
+// GroupBy selects how a report groups its tasks.
+type GroupBy string
+
+const (
+	GroupByNone    GroupBy = ""
+	GroupByProject GroupBy = "project"
+	GroupByDate    GroupBy = "date"
+	GroupByStatus  GroupBy = "status"
+)
+
+// ParseGroupBy checks a --group-by value.
+func ParseGroupBy(value string) (GroupBy, error) {
+	switch groupBy := GroupBy(strings.ToLower(value)); groupBy {
+	case GroupByNone, GroupByProject, GroupByDate, GroupByStatus:
+		return groupBy, nil
+	case "none":
+		return GroupByNone, nil
+	default:
+		return "", fmt.Errorf("unknown grouping %q, expected project, date or status", value)
+	}
+}
+
+// Renderer is a parsed report template, from text/template or html/template.
+type Renderer interface {
+	Execute(w io.Writer, data any) error
+}
+
+// ReportOptions controls how a report is rendered.
+type ReportOptions struct {
+	Template     Renderer // nil for DefaultTemplate
+	GroupBy      GroupBy
+	ProjectOrder []string   // projects listed first when grouping by project
+	Start, End   *time.Time // report period, defaults to the tasks' date range
+}
+
+// ReportData is passed to report templates.
+type ReportData struct {
+	Tasks   []model.Task // archived tasks in file order
+	Start   *time.Time   // earliest start date, nil if no task has one
+	End     *time.Time   // latest end (or start) date
+	GroupBy GroupBy      // empty when the report is not grouped
+	Groups  []TaskGroup  // tasks grouped by GroupBy
+}
+
+// TaskGroup is a set of tasks sharing a key, as returned by groupBy.
+type TaskGroup struct {
+	Key   string // project, status name or start date; empty if unset
+	Label string // heading text, e.g. "TADA", "Done" or "2025-09-13 - Sabtu"
+	Tasks []model.Task
+}
+
+// TemplateFuncs are the helpers available in report templates:
+//
+//	date "2006-01-02" .StartDate   format a date, empty when nil
+//	dateRange .StartDate .EndDate  "2025-09-13" or "2025-09-13 - 2025-09-14"
+//	day .StartDate                 day name in the current locale
+//	glyph .Status                  "x", "-" or " "
+//	checkbox .Status               "[x]", "[-]" or "[ ]"
+//	status .Status                 "done", "in-progress" or "todo"
+//	groupBy "project" .Tasks       groups by project, status or date, in
+//	                               the order each first appears
+//	subtasks .SubTasks             nested subtasks as a flat list with .Depth
+//	upper, lower, join, repeat     string helpers from the strings package
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"date":      formatTemplateDate,
+		"dateRange": model.FormatDateRange,
+		"day":       formatTemplateDay,
+		"glyph":     statusGlyph,
+		"checkbox":  func(status model.TaskStatus) string { return "[" + statusGlyph(status) + "]" },
+		"status":    func(status model.TaskStatus) string { return status.Name() },
+		"groupBy":   func(key string, tasks []model.Task) ([]TaskGroup, error) { return groupTasks(GroupBy(key), tasks) },
+		"subtasks":  flattenSubtasks,
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+		"join":      func(sep string, values []string) string { return strings.Join(values, sep) },
+		"repeat":    func(count int, value string) string { return strings.Repeat(value, count) },
+	}
+}
+
+// SubtaskLine is a subtask with its depth, 0 for top-level subtasks, as
+// listed by the subtasks template helper.
+type SubtaskLine struct {
+	model.Subtask
+	Depth int
+}
+
+func flattenSubtasks(subtasks []model.Subtask) []SubtaskLine {
+	var lines []SubtaskLine
+	model.WalkSubtasks(subtasks, func(subtask *model.Subtask, depth int) {
+		lines = append(lines, SubtaskLine{Subtask: *subtask, Depth: depth})
+	})
+	return lines
+}
+
+// ParseTemplate parses a report template with the helpers in TemplateFuncs.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(TemplateFuncs()).Parse(text)
+}
+
+// LoadTemplate reads and parses a report template file. An empty path
+// returns the built-in DefaultTemplate.
+func LoadTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return ParseTemplate("default", DefaultTemplate)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", path, err)
+	}
+
+	tmpl, err := ParseTemplate(filepath.Base(path), string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+// NewReportData collects the archived tasks, their date range and, if
+// requested, their groups.
+func NewReportData(sections []model.Section, options ReportOptions) (ReportData, error) {
+	data := ReportData{GroupBy: options.GroupBy}
+	for _, section := range sections {
+		if section.Name == model.SectionArchives {
+			data.Tasks = section.Tasks
+			break
+		}
+	}
+
+	for _, task := range data.Tasks {
+		if task.StartDate == nil {
+			continue
+		}
+		if data.Start == nil || task.StartDate.Before(*data.Start) {
+			data.Start = task.StartDate
+		}
+
+		end := task.StartDate
+		if task.EndDate != nil {
+			end = task.EndDate
+		}
+		if data.End == nil || end.After(*data.End) {
+			data.End = end
+		}
+	}
+
+	if options.Start != nil {
+		data.Start = options.Start
+	}
+	if options.End != nil {
+		data.End = options.End
+	}
+
+	if data.GroupBy != GroupByNone {
+		groups, err := groupTasks(data.GroupBy, data.Tasks)
+		if err != nil {
+			return data, err
+		}
+		sortGroups(data.GroupBy, groups, options.ProjectOrder)
+		data.Groups = groups
+	}
+
+	return data, nil
+}
+
+func formatTemplateDate(layout string, date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format(layout)
+}
+
+func formatTemplateDay(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return getDayName(*date)
+}
+
+func statusGlyph(status model.TaskStatus) string {
+	switch status {
+	case model.StatusDone:
+		return "x"
+	case model.StatusInProgress:
+		return "-"
+	default:
+		return " "
+	}
+}
+
+// groupTasks groups tasks by project, status or start date, keeping the
+// order in which each key first appears.
+func groupTasks(groupBy GroupBy, tasks []model.Task) ([]TaskGroup, error) {
+	var keyOf func(task model.Task) string
+	switch groupBy {
+	case GroupByProject:
+		keyOf = func(task model.Task) string { return task.Project }
+	case GroupByStatus:
+		keyOf = func(task model.Task) string { return task.Status.Name() }
+	case GroupByDate:
+		keyOf = func(task model.Task) string { return formatTemplateDate("2006-01-02", task.StartDate) }
+	default:
+		return nil, fmt.Errorf("unknown grouping %q, expected project, date or status", groupBy)
+	}
+
+	var groups []TaskGroup
+	index := make(map[string]int)
+	for _, task := range tasks {
+		k := keyOf(task)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, TaskGroup{Key: k, Label: groupLabel(groupBy, k, task)})
+		}
+		groups[i].Tasks = append(groups[i].Tasks, task)
+	}
+
+	return groups, nil
+}
+
+// sortGroups orders the groups of a grouped report: projects by
+// projectOrder, then alphabetically; dates newest first; statuses done, in
+// progress, todo. Groups without a project or date come last.
+func sortGroups(groupBy GroupBy, groups []TaskGroup, projectOrder []string) {
+	rank := groupRank(groupBy, projectOrder)
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i].Key, groups[j].Key
+		if a == "" || b == "" {
+			return b == "" && a != ""
+		}
+		if ra, rb := rank(a), rank(b); ra != rb {
+			return ra < rb
+		}
+		if groupBy == GroupByDate {
+			return a > b
+		}
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+}
+
+// groupRank returns a function ranking group keys that have a fixed
+// position; keys without one share the last rank.
+func groupRank(groupBy GroupBy, projectOrder []string) func(key string) int {
+	var order []string
+	switch groupBy {
+	case GroupByProject:
+		order = projectOrder
+	case GroupByStatus:
+		order = []string{model.StatusDone.Name(), model.StatusInProgress.Name(), model.StatusTodo.Name()}
+	}
+
+	return func(key string) int {
+		for i, k := range order {
+			if strings.EqualFold(k, key) {
+				return i
+			}
+		}
+		return len(order)
+	}
+}
+
+func groupLabel(groupBy GroupBy, key string, task model.Task) string {
+	switch groupBy {
+	case GroupByProject:
+		if key == "" {
+			return "No project"
+		}
+		return strings.ToUpper(key)
+	case GroupByDate:
+		if key == "" {
+			return "No date"
+		}
+		return key + " - " + formatTemplateDay(task.StartDate)
+	default:
+		switch task.Status {
+		case model.StatusDone:
+			return "Done"
+		case model.StatusInProgress:
+			return "In progress"
+		default:
+			return "Todo"
+		}
+	}
+}
@@ -2,32 +2,45 @@ package writer
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/ahmaruff/tada/internal/model"
 )
 
+// WriteInputFile writes the sections as a task file. The file is replaced
+// atomically, after a copy of the previous version is kept in .tada/backups.
 func WriteInputFile(sections []model.Section, filePath string) error {
 	content := GenerateInputMarkdown(sections)
-	return os.WriteFile(filePath, []byte(content), 0644)
+	return writeFile(filePath, []byte(content), true)
 }
 
-func WriteOutputFile(sections []model.Section, filePath string) error {
-	content := GenerateOutputMarkdown(sections)
-	return os.WriteFile(filePath, []byte(content), 0644)
+// WriteOutputFile renders the archived tasks as described by options and
+// writes the report.
+func WriteOutputFile(sections []model.Section, filePath string, options ReportOptions) error {
+	content, err := GenerateOutputMarkdown(sections, options)
+	if err != nil {
+		return err
+	}
+	return writeFile(filePath, []byte(content), false)
 }
 
 func GenerateInputMarkdown(sections []model.Section) string {
 	var result strings.Builder
 
 	for i, section := range sections {
-		if i > 0 {
-			result.WriteString("\n")
+		if section.Source != nil {
+			// Parsed sections carry their own surrounding lines
+			writeLines(&result, section.Source.Leading)
+			writeLines(&result, section.Source.Lines)
+			writeLines(&result, section.Source.Trailing)
+		} else {
+			if i > 0 {
+				result.WriteString("\n")
+			}
+			// Section header
+			result.WriteString(fmt.Sprintf("## %s\n", section.Name.Title()))
 		}
-		// Section header
-		result.WriteString(fmt.Sprintf("## %s\n", section.Name))
 
 		// Handle different section types
 		switch section.Name {
@@ -35,110 +48,66 @@ func GenerateInputMarkdown(sections []model.Section) string {
 			writeTasks(&result, section.Tasks, false)
 		case model.SectionTodo, model.SectionDone:
 			// These sections group tasks by date headers
-			writeTasksWithDateHeaders(&result, section.Tasks)
+			writeTasksWithDateHeaders(&result, section)
 		default:
 			writeTasks(&result, section.Tasks, false)
 		}
 	}
 
-	return result.String()
-}
-
-func GenerateOutputMarkdown(sections []model.Section) string {
-	var result strings.Builder
-
-	// Find Archives section
-	var archiveTasks []model.Task
+	// End lines as the parsed file did
+	content := result.String()
 	for _, section := range sections {
-		if section.Name == model.SectionArchives {
-			archiveTasks = section.Tasks
-			break
+		if section.Source == nil {
+			continue
 		}
-	}
-
-	for i, task := range archiveTasks {
-		if i > 0 {
-
-			result.WriteString("\n")
+		if section.Source.CRLF {
+			content = strings.ReplaceAll(content, "\n", "\r\n")
 		}
-		result.WriteString(taskToOutputMarkdown(task))
+		if section.Source.NoFinalNewline {
+			content = strings.TrimSuffix(content, "\n")
+			content = strings.TrimSuffix(content, "\r")
+		}
+		break
 	}
 
-	return result.String()
+	return content
 }
 
-// writeTasks writes tasks in the output format
-func taskToOutputMarkdown(task model.Task) string {
-	var result strings.Builder
-
-	// Merge project & title
-	var title string
-
-	if task.Project != "" {
-		title += strings.ToUpper(task.Project) + " - "
-	}
-
-	title += task.Title
-
-	// Title
-	fmt.Fprintf(&result, "# %s\n", title)
-
-	// Date range
-	if task.StartDate != nil && task.EndDate != nil {
-		if task.StartDate.Equal(*task.EndDate) {
-			fmt.Fprintf(&result, "%s  \n", task.StartDate.Format("2006-01-02"))
-		} else {
-			fmt.Fprintf(&result, "%s - %s  \n",
-				task.StartDate.Format("2006-01-02"),
-				task.EndDate.Format("2006-01-02"))
+func GenerateOutputMarkdown(sections []model.Section, options ReportOptions) (string, error) {
+	tmpl := options.Template
+	if tmpl == nil {
+		var err error
+		if tmpl, err = LoadTemplate(""); err != nil {
+			return "", err
 		}
 	}
 
-	// Description
-	if len(task.Description) > 0 {
-		fmt.Fprintf(&result, "Desc:  \n")
-		for _, desc := range task.Description {
-			fmt.Fprintf(&result, "  %s  \n", desc)
-		}
+	data, err := NewReportData(sections, options)
+	if err != nil {
+		return "", err
 	}
 
-	// Subtasks
-	for _, subtask := range task.SubTasks {
-		status := " "
-
-		switch subtask.Status {
-		case model.StatusDone:
-			status = "x"
-		case model.StatusInProgress:
-			status = "-"
-		}
-
-		fmt.Fprintf(&result, "  - [%s] %s\n", status, subtask.Content)
+	var result strings.Builder
+	if err := tmpl.Execute(&result, data); err != nil {
+		return "", fmt.Errorf("failed to render report: %w", err)
 	}
 
-	return result.String()
+	return result.String(), nil
 }
 
-// writeTasks writes tasks in the input format
 func writeTasks(result *strings.Builder, tasks []model.Task, useHeaderDate bool) {
 	for _, task := range tasks {
 		writeTask(result, task, useHeaderDate)
 	}
 }
 
-func writeTasksWithDateHeaders(result *strings.Builder, tasks []model.Task) {
+func writeTasksWithDateHeaders(result *strings.Builder, section model.Section) {
 	// Group tasks by date
 	dateGroups := make(map[string][]model.Task)
 	var dateOrder []string
 
-	for _, task := range tasks {
-		var dateKey string
-
-		if task.StartDate != nil {
-			dateKey = task.StartDate.Format("2006-01-02")
-		} else {
-			dateKey = "no-date"
-		}
+	for _, task := range section.Tasks {
+		dateKey := taskDateKey(task)
 
 		if _, exists := dateGroups[dateKey]; !exists {
 			dateOrder = append(dateOrder, dateKey)
@@ -146,13 +115,73 @@ func writeTasksWithDateHeaders(result *strings.Builder, tasks []model.Task) {
 		dateGroups[dateKey] = append(dateGroups[dateKey], task)
 	}
 
+	// Groups of a section written from scratch are separated by a blank
+	// line; new groups in a parsed section are followed by one instead
+	wroteGroup := false
+	startGroup := func() {
+		if section.Source == nil && wroteGroup {
+			result.WriteString("\n")
+		}
+		wroteGroup = true
+	}
+	endGroup := func() {
+		if section.Source != nil {
+			result.WriteString("\n")
+		}
+	}
+
+	// Date headers from the source file are kept even when they have no
+	// tasks left, in their original position
+	written := make(map[string]bool)
+	writeEmptyGroupsBefore := func(dateKey string) {
+		for _, group := range section.Groups {
+			if group.Date == dateKey {
+				return
+			}
+			if _, hasTasks := dateGroups[group.Date]; !hasTasks && !written[group.Date] {
+				written[group.Date] = true
+				if group.Source == nil {
+					// Groups that were not parsed, e.g. imported ones
+					startGroup()
+					writeDateHeader(result, group.Date, group.Label)
+					endGroup()
+					continue
+				}
+				writeLines(result, group.Source.Leading)
+				writeLines(result, group.Source.Lines)
+				writeLines(result, group.Source.Trailing)
+			}
+		}
+	}
+
 	for _, dateKey := range dateOrder {
 		if dateKey != "no-date" {
-			// Parse date back for formatting
-			if date, err := time.Parse("2006-01-02", dateKey); err == nil {
-				dayName := getDayName(date)
-				fmt.Fprintf(result, "### %s - %s\n", dateKey, dayName)
+			writeEmptyGroupsBefore(dateKey)
+		}
+		if group := findDateGroup(section.Groups, dateKey); group != nil {
+			writeLines(result, group.Source.Leading)
+			writeLines(result, group.Source.Lines)
+			writeLines(result, group.Source.Trailing)
+
+			for _, task := range dateGroups[dateKey] {
+				writeTask(result, task, true)
+			}
+			continue
+		}
+
+		startGroup()
+		if dateKey != "no-date" {
+			// Keep the label of a known group, even an empty one, otherwise
+			// use the day name
+			label := ""
+			if group := findGroup(section.Groups, dateKey); group != nil {
+				label = group.Label
+			} else if date, err := time.Parse("2006-01-02", dateKey); err == nil {
+				// Parse date back for formatting
+				label = getDayName(date)
 			}
+
+			writeDateHeader(result, dateKey, label)
 		}
 
 		tasks := dateGroups[dateKey]
@@ -160,11 +189,90 @@ func writeTasksWithDateHeaders(result *strings.Builder, tasks []model.Task) {
 			writeTask(result, task, true)
 		}
 
+		// Undated tasks of a parsed section are followed by the next
+		// header's own leading lines
+		if dateKey != "no-date" {
+			endGroup()
+		}
+	}
+
+	writeEmptyGroupsBefore("")
+}
+
+func writeDateHeader(result *strings.Builder, dateKey, label string) {
+	if label != "" {
+		fmt.Fprintf(result, "### %s - %s\n", dateKey, label)
+	} else {
+		fmt.Fprintf(result, "### %s\n", dateKey)
+	}
+}
+
+// taskDateKey returns the date header a task is written under. Unchanged
+// tasks stay under the header they were parsed from, and tasks without
+// source lines, such as imported ones, under the header they record.
+func taskDateKey(task model.Task) string {
+	if _, ok := unchangedSource(task, true); ok {
+		if task.Position.DateHeader != "" {
+			return task.Position.DateHeader
+		}
+		return "no-date"
+	}
+	if task.Source == nil && task.Position.DateHeader != "" {
+		return task.Position.DateHeader
+	}
+
+	if task.StartDate != nil {
+		return task.StartDate.Format("2006-01-02")
+	}
+	return "no-date"
+}
+
+func findDateGroup(groups []model.DateGroup, dateKey string) *model.DateGroup {
+	for i := range groups {
+		if groups[i].Date == dateKey && groups[i].Source != nil {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+
+func findGroup(groups []model.DateGroup, dateKey string) *model.DateGroup {
+	for i := range groups {
+		if groups[i].Date == dateKey {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+
+// unchangedSource returns the task's source lines if they can be written
+// as-is, i.e. the task was not modified since it was parsed and is written
+// into the same kind of section.
+func unchangedSource(task model.Task, useHeaderDate bool) (*model.Source, bool) {
+	source := task.Source
+	if source == nil || len(source.Lines) == 0 {
+		return nil, false
+	}
+	if task.Position.Section.HasDateGroups() != useHeaderDate {
+		return nil, false
+	}
+	return source, source.Fingerprint == task.Fingerprint()
+}
+
+func writeLines(result *strings.Builder, lines []string) {
+	for _, line := range lines {
+		result.WriteString(line)
 		result.WriteString("\n")
 	}
 }
 
 func writeTask(result *strings.Builder, task model.Task, useHeaderDate bool) {
+	if source, ok := unchangedSource(task, useHeaderDate); ok {
+		writeLines(result, source.Lines)
+		writeLines(result, source.Trailing)
+		return
+	}
+
 	// Task line with status and title
 	status := " "
 	switch task.Status {
@@ -188,8 +296,8 @@ func writeTask(result *strings.Builder, task model.Task, useHeaderDate bool) {
 		fmt.Fprintf(result, "  %s\n", desc)
 	}
 
-	// Write subtasks
-	for _, subtask := range task.SubTasks {
+	// Write subtasks, two more spaces per level
+	model.WalkSubtasks(task.SubTasks, func(subtask *model.Subtask, depth int) {
 		subtaskStatus := " "
 		switch subtask.Status {
 		case model.StatusDone:
@@ -197,7 +305,12 @@ func writeTask(result *strings.Builder, task model.Task, useHeaderDate bool) {
 		case model.StatusInProgress:
 			subtaskStatus = "-"
 		}
-		fmt.Fprintf(result, "  - [%s] %s\n", subtaskStatus, subtask.Content)
+		fmt.Fprintf(result, "%s- [%s] %s\n", strings.Repeat("  ", depth+1), subtaskStatus, subtask.Content)
+	})
+
+	// Keep notes written after the task
+	if task.Source != nil {
+		writeLines(result, task.Source.Trailing)
 	}
 }
 
@@ -214,13 +327,17 @@ func buildTaskComment(task model.Task, useHeaderDate bool) string {
 		parts = append(parts, fmt.Sprintf("#%s", task.ID))
 	}
 
-	// Add dates only if we're not relying on the header date
-	if !useHeaderDate {
-		if dateStr := formatTaskDates(task.StartDate, task.EndDate); dateStr != "" {
+	// Add dates unless the header date says it all
+	if dateStr := formatTaskDates(task.StartDate, task.EndDate); dateStr != "" {
+		if !useHeaderDate || dateStr != taskDateKey(task) {
 			parts = append(parts, dateStr)
 		}
 	}
 
+	if task.Reopened {
+		parts = append(parts, "reopened")
+	}
+
 	return strings.Join(parts, "|")
 }
 
@@ -238,31 +355,9 @@ func formatTaskDates(startDate, endDate *time.Time) string {
 	}
 
 	if startDate != nil {
-		return startDate.Format("2006-01-02")
+		// Open range, a single date would also set the end date
+		return startDate.Format("2006-01-02") + " -"
 	}
 
 	return endDate.Format("2006-01-02")
 }
-
-func datesEqual(startDate, endDate, headerDate *time.Time) bool {
-	if headerDate == nil || startDate == nil {
-		return false
-	}
-
-	// Check if task dates match the header date
-	return startDate.Equal(*headerDate) && (endDate == nil || endDate.Equal(*headerDate))
-}
-
-func getDayName(date time.Time) string {
-	// Indonesian day names
-	dayNames := map[time.Weekday]string{
-		time.Sunday:    "Minggu",
-		time.Monday:    "Senin",
-		time.Tuesday:   "Selasa",
-		time.Wednesday: "Rabu",
-		time.Thursday:  "Kamis",
-		time.Friday:    "Jum'at",
-		time.Saturday:  "Sabtu",
-	}
-	return dayNames[date.Weekday()]
-}
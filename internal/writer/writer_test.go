@@ -0,0 +1,466 @@
+package writer
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahmaruff/tada/internal/model"
+	"github.com/ahmaruff/tada/internal/parser"
+)
+
+const roundTripInput = `# My Tasks
+
+Some intro paragraph.
+
+## Backlog
+Backlog notes here.
+
+- [ ] Task one <!--   @crm|#1 -->
+  desc line
+
+  desc after blank
+- [x]   Spaced   title <!-- @crm|#2 -->
+A note after task two.
+
+## Todo
+
+### 2025-09-15 - Monday
+### 2025-09-14   - Sunday!
+- [x] Task one <!-- @crm|#1 -->
+
+- [-] Undated <!-- @x|2025-09-01 -->
+random
+
+## Done
+### 2025-09-13
+- [x] Spaced   title <!-- #2 -->
+
+## Archives
+- [x] some task
+trailing text at end
+`
+
+func parse(t *testing.T, input string) []model.Section {
+	t.Helper()
+
+	sections, err := parser.ParseContent(bufio.NewScanner(strings.NewReader(input)))
+	if err != nil {
+		t.Fatalf("ParseContent failed: %v", err)
+	}
+	return sections
+}
+
+func TestGenerateInputMarkdownRoundTrip(t *testing.T) {
+	sections := parse(t, roundTripInput)
+
+	result := GenerateInputMarkdown(sections)
+	if result != roundTripInput {
+		t.Errorf("Expected unchanged output, got:\n%s", result)
+	}
+}
+
+func TestGenerateInputMarkdownLineEndings(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"crlf", strings.ReplaceAll(roundTripInput, "\n", "\r\n")},
+		{"no final newline", strings.TrimSuffix(roundTripInput, "\n")},
+		{"crlf without final newline", strings.TrimSuffix(strings.ReplaceAll(roundTripInput, "\n", "\r\n"), "\r\n")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sections := parse(t, tt.input)
+			if result := GenerateInputMarkdown(sections); result != tt.input {
+				t.Errorf("Expected unchanged output %q, got %q", tt.input, result)
+			}
+
+			// Rewritten and new lines end the same way
+			sections[0].Tasks[0].Status = model.StatusDone
+			sections[len(sections)-1].Tasks = append(sections[len(sections)-1].Tasks,
+				model.Task{ID: "3", Title: "New", Status: model.StatusDone})
+			expected := strings.Replace(roundTripInput,
+				"- [ ] Task one <!--   @crm|#1 -->\n  desc line\n\n  desc after blank\n",
+				"- [x] Task one <!-- @crm|#1 -->\n  desc line\n  desc after blank\n", 1) +
+				"- [x] New <!-- #3 -->\n"
+			if strings.Contains(tt.input, "\r\n") {
+				expected = strings.ReplaceAll(expected, "\n", "\r\n")
+			}
+			if !strings.HasSuffix(tt.input, "\n") {
+				expected = strings.TrimSuffix(strings.TrimSuffix(expected, "\n"), "\r")
+			}
+			if result := GenerateInputMarkdown(sections); result != expected {
+				t.Errorf("Expected %q, got %q", expected, result)
+			}
+		})
+	}
+}
+
+func TestGenerateInputMarkdownRewritesChangedTasksOnly(t *testing.T) {
+	sections := parse(t, roundTripInput)
+
+	// Complete the first Backlog task
+	sections[0].Tasks[0].Status = model.StatusDone
+
+	result := GenerateInputMarkdown(sections)
+	expected := strings.Replace(roundTripInput,
+		"- [ ] Task one <!--   @crm|#1 -->\n  desc line\n\n  desc after blank\n",
+		"- [x] Task one <!-- @crm|#1 -->\n  desc line\n  desc after blank\n", 1)
+
+	if result != expected {
+		t.Errorf("Expected only the changed task to be rewritten, got:\n%s", result)
+	}
+}
+
+func TestGenerateInputMarkdownNewSections(t *testing.T) {
+	sections := []model.Section{
+		{
+			Name:  model.SectionBacklog,
+			Tasks: []model.Task{{ID: "1", Title: "Task", Project: "crm", Status: model.StatusTodo}},
+		},
+		{Name: model.SectionArchives},
+	}
+
+	expected := "## Backlog\n- [ ] Task <!-- @crm|#1 -->\n\n## Archives\n"
+	if result := GenerateInputMarkdown(sections); result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestGenerateInputMarkdownNestedSubtasks(t *testing.T) {
+	input := "## Backlog\n" +
+		"- [ ] API <!-- #1 -->\n" +
+		"  - [ ] auth\n" +
+		"      - [ ] tokens\n" +
+		"  - [ ] docs\n"
+
+	sections := parse(t, input)
+	if result := GenerateInputMarkdown(sections); result != input {
+		t.Errorf("Expected unchanged output, got:\n%s", result)
+	}
+
+	// A changed task is written with two spaces per level
+	sections[0].Tasks[0].SubTasks[0].Children[0].Status = model.StatusDone
+	expected := "## Backlog\n" +
+		"- [ ] API <!-- #1 -->\n" +
+		"  - [ ] auth\n" +
+		"    - [x] tokens\n" +
+		"  - [ ] docs\n"
+
+	if result := GenerateInputMarkdown(sections); result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestGenerateInputMarkdownLocale(t *testing.T) {
+	defer SetLocale(builtinLocales[DefaultLocale])
+
+	date := time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)
+	sections := []model.Section{
+		{
+			Name:  model.SectionTodo,
+			Tasks: []model.Task{{ID: "1", Title: "Task", Status: model.StatusTodo, StartDate: &date, EndDate: &date}},
+		},
+	}
+
+	locale, err := ParseLocale("en")
+	if err != nil {
+		t.Fatalf("ParseLocale failed: %v", err)
+	}
+	SetLocale(locale)
+
+	expected := "## Todo\n### 2025-01-16 - Thursday\n- [ ] Task <!-- #1 -->\n"
+	if result := GenerateInputMarkdown(sections); result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	// A label already set on the date group wins over the locale
+	sections[0].Groups = []model.DateGroup{{Date: "2025-01-16", Label: "Kamis"}}
+	expected = "## Todo\n### 2025-01-16 - Kamis\n- [ ] Task <!-- #1 -->\n"
+	if result := GenerateInputMarkdown(sections); result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		value    string
+		sunday   string
+		thursday string
+		wantErr  bool
+	}{
+		{"", "Minggu", "Kamis", false},
+		{"id", "Minggu", "Kamis", false},
+		{"EN", "Sunday", "Thursday", false},
+		{"Su,Mo,Tu,We,Th,Fr,Sa", "Su", "Th", false},
+		{"fr", "", "", true},
+		{"Su,Mo,,We,Th,Fr,Sa", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			locale, err := ParseLocale(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLocale failed: %v", err)
+			}
+			if locale.Days[time.Sunday] != tt.sunday || locale.Days[time.Thursday] != tt.thursday {
+				t.Errorf("Unexpected day names %v", locale.Days)
+			}
+		})
+	}
+}
+
+func TestGenerateOutputMarkdown(t *testing.T) {
+	date := func(day int) *time.Time {
+		d := time.Date(2025, 9, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	sections := []model.Section{
+		{Name: model.SectionBacklog, Tasks: []model.Task{{ID: "9", Title: "Not archived"}}},
+		{
+			Name: model.SectionArchives,
+			Tasks: []model.Task{
+				{
+					Title:       "Parser",
+					Project:     "tada",
+					Status:      model.StatusDone,
+					StartDate:   date(13),
+					EndDate:     date(14),
+					Description: []string{"line one", "line two"},
+					SubTasks: []model.Subtask{
+						{Status: model.StatusDone, Content: "lexer"},
+						{Status: model.StatusInProgress, Content: "errors", Children: []model.Subtask{
+							{Status: model.StatusDone, Content: "positions", Children: []model.Subtask{
+								{Status: model.StatusTodo, Content: "columns"},
+							}},
+						}},
+						{Status: model.StatusTodo, Content: "docs"},
+					},
+				},
+				{Title: "No project", Status: model.StatusDone, StartDate: date(14), EndDate: date(14)},
+			},
+		},
+	}
+
+	t.Run("default template", func(t *testing.T) {
+		expected := "# TADA - Parser\n" +
+			"2025-09-13 - 2025-09-14  \n" +
+			"Desc:  \n" +
+			"  line one  \n" +
+			"  line two  \n" +
+			"  - [x] lexer\n" +
+			"  - [-] errors\n" +
+			"    - [x] positions\n" +
+			"      - [ ] columns\n" +
+			"  - [ ] docs\n" +
+			"\n" +
+			"# No project\n" +
+			"2025-09-14  \n"
+
+		result, err := GenerateOutputMarkdown(sections, ReportOptions{})
+		if err != nil {
+			t.Fatalf("GenerateOutputMarkdown failed: %v", err)
+		}
+		if result != expected {
+			t.Errorf("Expected:\n%q\ngot:\n%q", expected, result)
+		}
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		SetLocale(builtinLocales["en"])
+		defer SetLocale(builtinLocales[DefaultLocale])
+
+		tmpl, err := ParseTemplate("custom", `{{date "Jan 2" .Start}}-{{date "Jan 2" .End}}
+{{range groupBy "date" .Tasks}}{{.Key}}:{{range .Tasks}} {{checkbox .Status}} {{lower .Project}}/{{.Title}} ({{day .StartDate}}, {{status .Status}}){{end}}
+{{end}}`)
+		if err != nil {
+			t.Fatalf("ParseTemplate failed: %v", err)
+		}
+
+		expected := "Sep 13-Sep 14\n" +
+			"2025-09-13: [x] tada/Parser (Saturday, done)\n" +
+			"2025-09-14: [x] /No project (Sunday, done)\n"
+
+		result, err := GenerateOutputMarkdown(sections, ReportOptions{Template: tmpl})
+		if err != nil {
+			t.Fatalf("GenerateOutputMarkdown failed: %v", err)
+		}
+		if result != expected {
+			t.Errorf("Expected:\n%q\ngot:\n%q", expected, result)
+		}
+	})
+
+	t.Run("unknown group key", func(t *testing.T) {
+		tmpl, err := ParseTemplate("bad", `{{groupBy "owner" .Tasks}}`)
+		if err != nil {
+			t.Fatalf("ParseTemplate failed: %v", err)
+		}
+		if _, err := GenerateOutputMarkdown(sections, ReportOptions{Template: tmpl}); err == nil {
+			t.Errorf("Expected error for unknown group key")
+		}
+	})
+}
+
+func TestGenerateGroupedOutputMarkdown(t *testing.T) {
+	date := func(day int) *time.Time {
+		d := time.Date(2025, 9, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	sections := []model.Section{
+		{
+			Name: model.SectionArchives,
+			Tasks: []model.Task{
+				{Title: "Login", Project: "crm", Status: model.StatusDone, StartDate: date(14), EndDate: date(14)},
+				{Title: "Notes", Status: model.StatusDone},
+				{Title: "Parser", Project: "tada", Status: model.StatusDone, StartDate: date(13), EndDate: date(13),
+					SubTasks: []model.Subtask{{Status: model.StatusDone, Content: "lexer"}}},
+				{Title: "Writer", Project: "tada", Status: model.StatusInProgress, StartDate: date(13), EndDate: date(14)},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		options  ReportOptions
+		expected string
+	}{
+		{
+			name:    "project order",
+			options: ReportOptions{GroupBy: GroupByProject, ProjectOrder: []string{"TADA"}},
+			expected: "# TADA (2)\n" +
+				"\n## Parser\n2025-09-13  \n  - [x] lexer\n" +
+				"\n## Writer\n2025-09-13 - 2025-09-14  \n" +
+				"\n# CRM (1)\n" +
+				"\n## Login\n2025-09-14  \n" +
+				"\n# No project (1)\n" +
+				"\n## Notes\n",
+		},
+		{
+			name:    "date",
+			options: ReportOptions{GroupBy: GroupByDate},
+			expected: "# 2025-09-14 - Minggu (1)\n" +
+				"\n## CRM - Login\n2025-09-14  \n" +
+				"\n# 2025-09-13 - Sabtu (2)\n" +
+				"\n## TADA - Parser\n2025-09-13  \n  - [x] lexer\n" +
+				"\n## TADA - Writer\n2025-09-13 - 2025-09-14  \n" +
+				"\n# No date (1)\n" +
+				"\n## Notes\n",
+		},
+		{
+			name:    "status",
+			options: ReportOptions{GroupBy: GroupByStatus},
+			expected: "# Done (3)\n" +
+				"\n## CRM - Login\n2025-09-14  \n" +
+				"\n## Notes\n" +
+				"\n## TADA - Parser\n2025-09-13  \n  - [x] lexer\n" +
+				"\n# In progress (1)\n" +
+				"\n## TADA - Writer\n2025-09-13 - 2025-09-14  \n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GenerateOutputMarkdown(sections, tt.options)
+			if err != nil {
+				t.Fatalf("GenerateOutputMarkdown failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseGroupBy(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected GroupBy
+		wantErr  bool
+	}{
+		{"", GroupByNone, false},
+		{"none", GroupByNone, false},
+		{"Project", GroupByProject, false},
+		{"date", GroupByDate, false},
+		{"status", GroupByStatus, false},
+		{"owner", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			groupBy, err := ParseGroupBy(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if groupBy != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, groupBy)
+			}
+		})
+	}
+}
+
+func TestGenerateHTMLReport(t *testing.T) {
+	date := func(day int) *time.Time {
+		d := time.Date(2025, 9, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	sections := []model.Section{
+		{
+			Name: model.SectionArchives,
+			Tasks: []model.Task{
+				{Title: "Fix <script> & escaping", Project: "crm", Status: model.StatusDone, StartDate: date(13), EndDate: date(14),
+					Description: []string{"Use html/template"},
+					SubTasks: []model.Subtask{{Status: model.StatusInProgress, Content: "audit",
+						Children: []model.Subtask{{Status: model.StatusDone, Content: "headers"}}}}},
+				{Title: "Parser", Project: "tada", Status: model.StatusInProgress, StartDate: date(14)},
+			},
+		},
+	}
+
+	tmpl, err := LoadHTMLTemplate("")
+	if err != nil {
+		t.Fatalf("LoadHTMLTemplate failed: %v", err)
+	}
+
+	result, err := GenerateOutputMarkdown(sections, ReportOptions{Template: tmpl, GroupBy: GroupByProject})
+	if err != nil {
+		t.Fatalf("GenerateOutputMarkdown failed: %v", err)
+	}
+
+	for _, expected := range []string{
+		"<!DOCTYPE html>",
+		"<style>",
+		`<p class="period">2025-09-13 - 2025-09-14</p>`,
+		`<h2>CRM <span class="count">(1)</span></h2>`,
+		`<span class="badge done">done</span>`,
+		`<span class="badge in-progress">in-progress</span>`,
+		`<span class="title">Fix &lt;script&gt; &amp; escaping</span>`,
+		`<span class="dates">2025-09-13 - 2025-09-14</span>`,
+		"<details>\n<summary>Details</summary>",
+		"<li class=\"in-progress\">audit\n<ul class=\"subtasks\">\n<li class=\"done\">headers</li>\n</ul></li>",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected HTML to contain %q, got:\n%s", expected, result)
+		}
+	}
+
+	for _, external := range []string{"<script", "<link", "src=", "http://", "https://"} {
+		if strings.Contains(result, external) {
+			t.Errorf("Expected a self-contained page, found %q", external)
+		}
+	}
+	if !strings.HasSuffix(result, "</html>\n") {
+		t.Errorf("Expected the page to end with </html>, got %q", result[len(result)-20:])
+	}
+}